@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/markdown"
+	"github.com/urfave/cli/v2"
+)
+
+var exportCommand = &cli.Command{
+	Name:      "export",
+	Usage:     "Exports the zettel with the given title or alias as markdown, with its embeds expanded",
+	ArgsUsage: "<query>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "File to write to instead of stdout",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() == 0 {
+			return fmt.Errorf("usage: zet export <query>")
+		}
+		query := strings.Join(c.Args().Slice(), " ")
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+
+		zettels, err := svc.FindByName(c.Context, query)
+		if err != nil {
+			return err
+		}
+		if len(zettels) == 0 {
+			return fmt.Errorf("no zettel matches %q", query)
+		}
+		z, err := chooseZettel(zettels, "Zettel to export: ")
+		if err != nil {
+			return err
+		}
+
+		// embeds are found among the zettels of the first workspace of z,
+		// then everywhere else
+		workspaceID := uuid.Nil
+		if wrk, err := svc.DefaultWorkspace(c.Context, z.ID()); err == nil {
+			workspaceID = wrk.ID()
+		}
+		body := svc.Transclude(c.Context, z, workspaceID, markdown.WrapQuote)
+
+		var w io.Writer = os.Stdout
+		if c.IsSet("output") {
+			f, err := os.Create(c.String("output"))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		_, err = fmt.Fprintf(w, "# %s\n\n%s\n", z.Title(), strings.TrimSpace(body))
+		return err
+	},
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/graph"
+	"github.com/urfave/cli/v2"
+)
+
+// graphFlags select the part of the graph a graph command works on.
+var graphFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "workspace",
+		Aliases: []string{"w"},
+		Usage:   "Only include zettels of the workspace with this ID",
+	},
+	&cli.StringFlag{
+		Name:    "kind",
+		Aliases: []string{"k"},
+		Usage:   "Only include zettels of this kind",
+	},
+	&cli.StringFlag{
+		Name:    "tag",
+		Aliases: []string{"t"},
+		Usage:   "Only include zettels with this tag",
+	},
+	&cli.StringFlag{
+		Name:  "root",
+		Usage: "Only include the neighbourhood of the zettel with this ID",
+	},
+	&cli.IntFlag{
+		Name:  "depth",
+		Value: 1,
+		Usage: "Number of links away from --root to include",
+	},
+}
+
+var graphCommand = &cli.Command{
+	Name:  "graph",
+	Usage: "Works with the graph formed by the links between zettels",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "export",
+			Usage: "Exports the graph to a file format other tools can visualise",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Value:   string(graph.DOT),
+					Usage:   fmt.Sprintf("Output format, one of %v", graph.Formats),
+				},
+				&cli.StringFlag{
+					Name:    "output",
+					Aliases: []string{"o"},
+					Usage:   "File to write to instead of stdout",
+				},
+			}, graphFlags...),
+			Action: func(c *cli.Context) error {
+				g, err := loadGraph(c)
+				if err != nil {
+					return err
+				}
+
+				var w io.Writer = os.Stdout
+				if c.IsSet("output") {
+					f, err := os.Create(c.String("output"))
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					w = f
+				}
+
+				return g.Export(w, graph.Format(c.String("format")))
+			},
+		},
+		graphHubsCommand,
+		graphIslandsCommand,
+		graphPathCommand,
+	},
+}
+
+// loadGraph builds the graph and applies the graphFlags to it.
+func loadGraph(c *cli.Context) (*graph.Graph, error) {
+	var filter graph.Filter
+	if c.IsSet("workspace") {
+		id, err := uuid.Parse(c.String("workspace"))
+		if err != nil {
+			return nil, err
+		}
+		filter.WorkspaceID = id
+	}
+	filter.Kind = zettel.Kind(c.String("kind"))
+	filter.Tag = c.String("tag")
+
+	svc, err := newService()
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := svc.Graph(c.Context)
+	if err != nil {
+		return nil, err
+	}
+	g = g.Filter(filter)
+
+	if c.IsSet("root") {
+		root, err := uuid.Parse(c.String("root"))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := g.Node(root); !ok {
+			return nil, zettel.ErrZettelNotFound
+		}
+		g = g.Neighbourhood(root, c.Int("depth"))
+	}
+
+	return g, nil
+}
+
+var graphHubsCommand = &cli.Command{
+	Name:  "hubs",
+	Usage: "Lists the most central zettels",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "by",
+			Value: "pagerank",
+			Usage: "Centrality measure, pagerank or degree",
+		},
+		&cli.IntFlag{
+			Name:    "limit",
+			Aliases: []string{"n"},
+			Value:   10,
+			Usage:   "Number of zettels to list",
+		},
+	}, graphFlags...),
+	Action: func(c *cli.Context) error {
+		g, err := loadGraph(c)
+		if err != nil {
+			return err
+		}
+
+		var scores []graph.Score
+		switch c.String("by") {
+		case "pagerank":
+			scores = g.PageRank(graph.DefaultDamping, graph.DefaultIterations)
+		case "degree":
+			scores = g.Degree()
+		default:
+			return fmt.Errorf("unknown centrality measure %q", c.String("by"))
+		}
+
+		for i, score := range scores {
+			if i == c.Int("limit") {
+				break
+			}
+			fmt.Printf("%3d %8.4f %s %s\n", i+1, score.Value, score.Node.ID, score.Node.Title)
+		}
+		return nil
+	},
+}
+
+var graphIslandsCommand = &cli.Command{
+	Name:  "islands",
+	Usage: "Lists the groups of zettels that are not linked to the largest one",
+	Flags: graphFlags,
+	Action: func(c *cli.Context) error {
+		g, err := loadGraph(c)
+		if err != nil {
+			return err
+		}
+
+		for i, island := range g.Islands() {
+			fmt.Printf("island %d (%d zettels)\n", i+1, len(island))
+			for _, n := range island {
+				fmt.Printf("  %s %s\n", n.ID, n.Title)
+			}
+		}
+		return nil
+	},
+}
+
+var graphPathCommand = &cli.Command{
+	Name:      "path",
+	Usage:     "Finds the shortest path of links between two zettels",
+	ArgsUsage: "<a> <b>",
+	Flags:     graphFlags,
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return fmt.Errorf("usage: zet graph path <a> <b>")
+		}
+
+		from, err := uuid.Parse(c.Args().Get(0))
+		if err != nil {
+			return err
+		}
+		to, err := uuid.Parse(c.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		g, err := loadGraph(c)
+		if err != nil {
+			return err
+		}
+
+		path, err := g.ShortestPath(from, to)
+		if err != nil {
+			return err
+		}
+		for i, n := range path {
+			fmt.Printf("%3d %s %s\n", i, n.ID, n.Title)
+		}
+		return nil
+	},
+}
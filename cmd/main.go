@@ -4,14 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 
-	"github.com/odas0r/zet/pkg/controllers"
 	"github.com/odas0r/zet/pkg/database"
-	"github.com/odas0r/zet/pkg/router"
-	"github.com/odas0r/zet/pkg/router/middleware"
+	wq "github.com/odas0r/zet/pkg/domain/workspace/sqlite"
+	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/service"
 	"github.com/pressly/goose/v3"
 	"github.com/urfave/cli/v2"
 
@@ -27,72 +26,19 @@ func main() {
 		Flags:                []cli.Flag{},
 		EnableBashCompletion: true,
 		Commands: []*cli.Command{
-			{
-				Name:  "serve",
-				Usage: "Starts the web server",
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:    "port",
-						Aliases: []string{"p"},
-						Value:   "3000",
-						Usage:   "Port to listen on",
-					},
-					&cli.BoolFlag{
-						Name:  "dev",
-						Value: false,
-						Usage: "Enable development mode",
-					},
-					&cli.StringFlag{
-						Name:  "address",
-						Value: "localhost",
-						Usage: "Address to listen on",
-					},
-				},
-				Action: func(c *cli.Context) error {
-					port := c.String("port")
-					dev := c.Bool("dev")
-
-					db := database.New(database.Options{
-						URL:        "zettel.db",
-						LogQueries: true,
-					})
-
-					controller, err := controllers.NewController(db)
-					if err != nil {
-						log.Fatalf("failed to create controller: %v", err)
-					}
-
-					r := router.New()
-					rr := r.Group("/")
-					rr.Use(middleware.WithMethods("GET", "POST", "DELETE"))
-					rr.Use(middleware.WithLayout)
-					rr.Use(middleware.WithLogger)
-
-					rr.HandleFunc("GET /", controller.HandleHome)
-					rr.HandleFunc("GET /workspaces", controller.HandleListWorkspaces)
-					rr.HandleFunc("GET /workspaces/create", controller.HandleCreateWorkspaceForm)
-					rr.HandleFunc("POST /workspaces/create", controller.HandleCreateWorkspace)
-					rr.HandleFunc("GET /workspaces/edit/{id}", controller.HandleEditWorkspaceForm)
-					rr.HandleFunc("POST /workspaces/edit/{id}", controller.HandleEditWorkspace)
-					rr.HandleFunc("DELETE /workspaces/delete/{id}", controller.HandleDeleteWorkspace)
-					rr.HandleFunc("GET /workspaces/{id}", controller.HandleListZettels)
-
-					rr.HandleFunc("GET /workspaces/{id}/zettels/create", controller.HandleCreateZettelForm)
-					rr.HandleFunc("POST /workspaces/{id}/zettels/create", controller.HandleCreateZettel)
-
-					rr.HandleFunc("GET /workspaces/{id}/zettels/edit/{zettelId}", controller.HandleEditZettelForm)
-					rr.HandleFunc("POST /workspaces/{id}/zettels/edit/{zettelId}", controller.HandleEditZettel)
-					rr.HandleFunc("DELETE /workspaces/{id}/zettels/delete/{zettelId}", controller.HandleDeleteZettel)
-
-					r.Handle("GET /public/",
-						http.StripPrefix("/public/", http.FileServer(http.Dir("public"))),
-						middleware.WithDisableCache(dev),
-					)
-
-					log.Printf("Listening on :%s\n", port)
-					return http.ListenAndServe(fmt.Sprintf(":%s", port), r)
-				},
-			},
+			newCommand,
+			openCommand,
+			mvCommand,
+			mergeCommand,
+			splitCommand,
+			nextCommand,
+			prevCommand,
+			childrenCommand,
+			graphCommand,
+			exportCommand,
+			serveCommand,
+			routesCommand,
+			tokenCommand,
 			{
 				Name:  "migrate",
 				Usage: "Migrate the database to the latest version",
@@ -230,3 +176,22 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// newService connects to the database and wires the repositories used by the
+// command line.
+func newService() (*service.Service, error) {
+	db := database.New(database.Options{
+		URL: "zettel.db",
+	})
+
+	workspaceRepo, err := wq.New(db)
+	if err != nil {
+		return nil, err
+	}
+	zettelRepo, err := zq.New(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.New(workspaceRepo, zettelRepo), nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/fs"
+	"github.com/urfave/cli/v2"
+)
+
+var mergeCommand = &cli.Command{
+	Name:      "merge",
+	Usage:     "Merges a zettel into another one, preserving its links",
+	ArgsUsage: "<keep> <absorb>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "interactive",
+			Aliases: []string{"i"},
+			Value:   false,
+			Usage:   "Combine the content with $EDITOR before saving",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return fmt.Errorf("usage: zet merge <keep> <absorb>")
+		}
+
+		keepID, err := uuid.Parse(c.Args().Get(0))
+		if err != nil {
+			return err
+		}
+		absorbID, err := uuid.Parse(c.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+
+		var combine func(string) (string, error)
+		if c.Bool("interactive") {
+			combine = editContent
+		}
+
+		z, err := svc.Merge(c.Context, keepID, absorbID, combine)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Merged into %s %s\n", z.ID(), z.Title())
+		return nil
+	},
+}
+
+// editContent opens the content in $EDITOR and returns the edited version.
+func editContent(content string) (string, error) {
+	f, err := os.CreateTemp("", "zet-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer fs.Remove(f.Name())
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := fs.Editor(f.Name()); err != nil {
+		return "", err
+	}
+
+	return fs.Read(f.Name())
+}
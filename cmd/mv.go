@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/fs"
+	"github.com/urfave/cli/v2"
+)
+
+var mvCommand = &cli.Command{
+	Name:      "mv",
+	Usage:     "Renames a zettel and rewrites every reference to it",
+	ArgsUsage: "<id> <title>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Value:   false,
+			Usage:   "Do not ask for confirmation",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return fmt.Errorf("usage: zet mv <id> <title>")
+		}
+
+		id, err := uuid.Parse(c.Args().Get(0))
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+
+		plan, err := svc.PlanRename(c.Context, id, c.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Renaming %q to %q\n", plan.OldTitle, plan.NewTitle)
+		for _, z := range plan.Affected {
+			fmt.Printf("  update %s %s\n", z.ID(), z.Title())
+		}
+		for _, f := range plan.Files {
+			fmt.Printf("  move   %s -> %s\n", f.From, f.To)
+		}
+
+		if !c.Bool("yes") && !fs.InputConfirm("Apply changes?") {
+			return nil
+		}
+
+		return svc.Rename(c.Context, plan)
+	},
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/urfave/cli/v2"
+)
+
+var newCommand = &cli.Command{
+	Name:      "new",
+	Usage:     "Create a new zettel",
+	ArgsUsage: "<title>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "kind",
+			Aliases: []string{"k"},
+			Value:   string(zettel.Fleet),
+			Usage:   "Kind of the zettel, permanent or fleet",
+		},
+		&cli.StringFlag{
+			Name:  "content",
+			Usage: "Content of the zettel, written in $EDITOR when omitted",
+		},
+		&cli.StringFlag{
+			Name:    "workspace",
+			Aliases: []string{"w"},
+			Usage:   "ID of the workspace, defaults to the one of --after or --branch",
+		},
+		&cli.StringFlag{
+			Name:  "after",
+			Usage: "ID of the zettel the new one continues in the sequence",
+		},
+		&cli.StringFlag{
+			Name:  "branch",
+			Usage: "ID of the zettel the new one branches off in the sequence",
+		},
+		&cli.BoolFlag{
+			Name:  "root",
+			Value: false,
+			Usage: "Start a new top-level sequence",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		title := strings.Join(c.Args().Slice(), " ")
+		if title == "" {
+			return fmt.Errorf("usage: zet new <title>")
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+
+		// the zettel the new one is placed relative to in the sequence
+		target := uuid.Nil
+		branch := c.IsSet("branch")
+		if c.IsSet("after") && branch {
+			return fmt.Errorf("--after and --branch cannot be used together")
+		} else if c.IsSet("after") || branch {
+			arg := c.String("after")
+			if branch {
+				arg = c.String("branch")
+			}
+			if target, err = uuid.Parse(arg); err != nil {
+				return err
+			}
+		}
+
+		workspaceID := uuid.Nil
+		if c.IsSet("workspace") {
+			if workspaceID, err = uuid.Parse(c.String("workspace")); err != nil {
+				return err
+			}
+		} else {
+			wrk, err := svc.DefaultWorkspace(c.Context, target)
+			if err != nil {
+				return err
+			}
+			workspaceID = wrk.ID()
+		}
+
+		content := c.String("content")
+		if content == "" {
+			if content, err = editContent("# " + title + "\n\n"); err != nil {
+				return err
+			}
+		}
+
+		z, err := zettel.New(title, content, zettel.Kind(c.String("kind")))
+		if err != nil {
+			return err
+		}
+		if err := z.ReplaceAliases(nil); err != nil {
+			return err
+		}
+
+		if target != uuid.Nil {
+			seq, err := svc.AllocateSequence(c.Context, workspaceID, target, branch)
+			if err != nil {
+				return err
+			}
+			z.SetSequence(seq)
+		} else if c.Bool("root") {
+			seq, err := svc.AllocateRootSequence(c.Context, workspaceID)
+			if err != nil {
+				return err
+			}
+			z.SetSequence(seq)
+		}
+
+		if err := svc.CreateZettel(c.Context, z, workspaceID); err != nil {
+			return err
+		}
+
+		printSequenced(z)
+		return nil
+	},
+}
+
+func printSequenced(z zettel.Zettel) {
+	if z.Sequence() != "" {
+		fmt.Printf("%s %s %s\n", z.Sequence(), z.ID(), z.Title())
+	} else {
+		fmt.Printf("%s %s\n", z.ID(), z.Title())
+	}
+}
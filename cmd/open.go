@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/fs"
+	"github.com/urfave/cli/v2"
+)
+
+var openCommand = &cli.Command{
+	Name:      "open",
+	Usage:     "Opens the zettel with the given title or alias in $EDITOR",
+	ArgsUsage: "<query>",
+	Action: func(c *cli.Context) error {
+		if c.NArg() == 0 {
+			return fmt.Errorf("usage: zet open <query>")
+		}
+		query := strings.Join(c.Args().Slice(), " ")
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+
+		zettels, err := svc.FindByName(c.Context, query)
+		if err != nil {
+			return err
+		}
+		if len(zettels) == 0 {
+			return fmt.Errorf("no zettel matches %q", query)
+		}
+
+		z, err := chooseZettel(zettels, "Zettel to open: ")
+		if err != nil {
+			return err
+		}
+		if err := svc.Visit(c.Context, z.ID()); err != nil {
+			return err
+		}
+
+		path, err := svc.FilePath(c.Context, z)
+		if err != nil {
+			return err
+		}
+		if path != "" {
+			return fs.Editor(path)
+		}
+
+		// without a backing file the content is edited in place
+		content, err := editContent(z.Content())
+		if err != nil {
+			return err
+		}
+		if content == z.Content() {
+			return nil
+		}
+		z.SetBody(content)
+		if err := z.ReplaceAliases(z.Aliases()); err != nil {
+			return err
+		}
+		return svc.SaveZettel(c.Context, z, uuid.Nil)
+	},
+}
+
+// chooseZettel asks which of the matching zettels is meant, unless there is
+// only one.
+func chooseZettel(zettels []zettel.Zettel, prompt string) (zettel.Zettel, error) {
+	if len(zettels) == 1 {
+		return zettels[0], nil
+	}
+	for i, match := range zettels {
+		fmt.Printf("%3d %s", i+1, match.Title())
+		if len(match.Aliases()) > 0 {
+			fmt.Printf(" (%s)", strings.Join(match.Aliases(), ", "))
+		}
+		fmt.Println()
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(fs.Input(prompt)))
+	if err != nil || n < 1 || n > len(zettels) {
+		return zettel.Zettel{}, fmt.Errorf("invalid choice")
+	}
+	return zettels[n-1], nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+
+	"github.com/odas0r/zet/pkg/database"
+	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/events"
+	"github.com/urfave/cli/v2"
+)
+
+var routesCommand = &cli.Command{
+	Name:  "routes",
+	Usage: "Lists the routes of the web server with their middleware",
+	Action: func(c *cli.Context) error {
+		db := database.New(database.Options{
+			URL: "zettel.db",
+		})
+
+		zettelRepo, err := zq.New(db)
+		if err != nil {
+			return err
+		}
+
+		r, err := newRouter(db, events.NewWatcher(zettelRepo, events.DefaultInterval), serveOptions{Auth: true})
+		if err != nil {
+			return err
+		}
+		r.PrintRoutes(os.Stdout)
+		return nil
+	},
+}
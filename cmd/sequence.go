@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/service"
+	"github.com/urfave/cli/v2"
+)
+
+// sequenceFlags select the workspace whose sequence is followed, as the
+// sequence identifiers are unique in a workspace only.
+var sequenceFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "workspace",
+		Aliases: []string{"w"},
+		Usage:   "ID of the workspace, defaults to the first one of the zettel",
+	},
+}
+
+var nextCommand = &cli.Command{
+	Name:      "next",
+	Usage:     "Retrieves the zettel that continues the given one in the sequence",
+	ArgsUsage: "<id>",
+	Flags:     sequenceFlags,
+	Action: func(c *cli.Context) error {
+		id, err := uuid.Parse(c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+		workspaceID, err := sequenceWorkspace(c, svc, id)
+		if err != nil {
+			return err
+		}
+
+		z, err := svc.NextInSequence(c.Context, workspaceID, id)
+		if err != nil {
+			return err
+		}
+		printSequenced(z)
+		return nil
+	},
+}
+
+var prevCommand = &cli.Command{
+	Name:      "prev",
+	Usage:     "Retrieves the zettel the given one continues in the sequence",
+	ArgsUsage: "<id>",
+	Flags:     sequenceFlags,
+	Action: func(c *cli.Context) error {
+		id, err := uuid.Parse(c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+		workspaceID, err := sequenceWorkspace(c, svc, id)
+		if err != nil {
+			return err
+		}
+
+		z, err := svc.PrevInSequence(c.Context, workspaceID, id)
+		if err != nil {
+			return err
+		}
+		printSequenced(z)
+		return nil
+	},
+}
+
+var childrenCommand = &cli.Command{
+	Name:      "children",
+	Usage:     "Retrieves the zettels that branch off the given one in the sequence",
+	ArgsUsage: "<id>",
+	Flags:     sequenceFlags,
+	Action: func(c *cli.Context) error {
+		id, err := uuid.Parse(c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+		workspaceID, err := sequenceWorkspace(c, svc, id)
+		if err != nil {
+			return err
+		}
+
+		zettels, err := svc.ChildrenInSequence(c.Context, workspaceID, id)
+		if err != nil {
+			return err
+		}
+		if len(zettels) == 0 {
+			return fmt.Errorf("no zettel branches off %s", id)
+		}
+		for _, z := range zettels {
+			printSequenced(z)
+		}
+		return nil
+	},
+}
+
+// sequenceWorkspace returns the workspace given by the sequenceFlags, or the
+// first workspace of the zettel.
+func sequenceWorkspace(c *cli.Context, svc *service.Service, id uuid.UUID) (uuid.UUID, error) {
+	if c.IsSet("workspace") {
+		return uuid.Parse(c.String("workspace"))
+	}
+	wrk, err := svc.DefaultWorkspace(c.Context, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return wrk.ID(), nil
+}
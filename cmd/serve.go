@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/odas0r/zet/pkg/auth"
+	"github.com/odas0r/zet/pkg/controllers"
+	"github.com/odas0r/zet/pkg/controllers/api"
+	"github.com/odas0r/zet/pkg/database"
+	tq "github.com/odas0r/zet/pkg/domain/token/sqlite"
+	wq "github.com/odas0r/zet/pkg/domain/workspace/sqlite"
+	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/events"
+	"github.com/odas0r/zet/pkg/health"
+	"github.com/odas0r/zet/pkg/logger"
+	"github.com/odas0r/zet/pkg/metrics"
+	"github.com/odas0r/zet/pkg/router"
+	"github.com/odas0r/zet/pkg/router/middleware"
+	"github.com/odas0r/zet/pkg/server"
+	"github.com/odas0r/zet/pkg/static"
+	"github.com/urfave/cli/v2"
+)
+
+var serveCommand = &cli.Command{
+	Name:  "serve",
+	Usage: "Starts the web server",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "port",
+			Aliases: []string{"p"},
+			Value:   defaults.Port,
+			Usage:   "Port to listen on",
+		},
+		&cli.BoolFlag{
+			Name:  "dev",
+			Value: false,
+			Usage: "Enable development mode",
+		},
+		&cli.BoolFlag{
+			Name:  "no-auth",
+			Value: false,
+			Usage: "Let anyone who can reach the server in, without a token",
+		},
+		&cli.StringFlag{
+			Name:    "session-secret",
+			EnvVars: []string{"ZET_SESSION_SECRET"},
+			Usage:   "Secret that signs the login sessions, random by default so sessions end on restart",
+		},
+		&cli.StringFlag{
+			Name:  "address",
+			Value: defaults.Address,
+			Usage: "Address to listen on",
+		},
+		&cli.StringFlag{
+			Name:  "log-format",
+			Value: logger.FormatText,
+			Usage: "Format of the logs: text or json",
+		},
+		&cli.StringFlag{
+			Name:  "log-level",
+			Value: "info",
+			Usage: "Least level logged: debug, which also logs the queries, info, warn or error",
+		},
+		&cli.StringFlag{
+			Name:  "socket",
+			Usage: "Unix socket to listen on instead of the address and port",
+		},
+		&cli.StringFlag{
+			Name:  "tls-cert",
+			Usage: "Certificate file to serve HTTPS with, along with --tls-key",
+		},
+		&cli.StringFlag{
+			Name:  "tls-key",
+			Usage: "Private key file of the --tls-cert certificate",
+		},
+		&cli.BoolFlag{
+			Name:  "tls-self-signed",
+			Value: false,
+			Usage: "Serve HTTPS with a self-signed certificate generated on start",
+		},
+		&cli.DurationFlag{
+			Name:  "events-interval",
+			Value: events.DefaultInterval,
+			Usage: "How often to look for zettels changed outside of the server, e.g. by the CLI, to refresh the pages",
+		},
+		&cli.DurationFlag{
+			Name:  "read-header-timeout",
+			Value: defaults.ReadHeaderTimeout,
+			Usage: "Time to read the headers of a request",
+		},
+		&cli.DurationFlag{
+			Name:  "read-timeout",
+			Value: defaults.ReadTimeout,
+			Usage: "Time to read a whole request",
+		},
+		&cli.DurationFlag{
+			Name:  "write-timeout",
+			Value: defaults.WriteTimeout,
+			Usage: "Time to write a response",
+		},
+		&cli.DurationFlag{
+			Name:  "idle-timeout",
+			Value: defaults.IdleTimeout,
+			Usage: "Time a kept-alive connection waits for the next request",
+		},
+		&cli.IntFlag{
+			Name:  "max-header-bytes",
+			Value: defaults.MaxHeaderBytes,
+			Usage: "Maximum size of the headers of a request",
+		},
+		&cli.DurationFlag{
+			Name:  "shutdown-timeout",
+			Value: defaults.ShutdownTimeout,
+			Usage: "Time the requests in flight have to finish when the server stops",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		level, err := logger.ParseLevel(c.String("log-level"))
+		if err != nil {
+			return err
+		}
+		l, err := logger.New(os.Stderr, c.String("log-format"), level)
+		if err != nil {
+			return err
+		}
+		slog.SetDefault(l)
+
+		opts := serveOptions{
+			Dev:           c.Bool("dev"),
+			Auth:          !c.Bool("no-auth"),
+			SessionSecret: c.String("session-secret"),
+		}
+		if !opts.Auth {
+			slog.Warn("authentication is disabled, anyone who can reach the server can change the zettels")
+		}
+
+		db := database.New(database.Options{
+			URL:        "zettel.db",
+			LogQueries: true,
+		})
+		defer func() {
+			if err := db.Close(); err != nil {
+				slog.Error("failed to close the database", "error", err)
+			}
+		}()
+
+		zettelRepo, err := zq.New(db)
+		if err != nil {
+			return fmt.Errorf("failed to create zettel repository: %w", err)
+		}
+		watcher := events.NewWatcher(zettelRepo, c.Duration("events-interval"))
+
+		r, err := newRouter(db, watcher, opts)
+		if err != nil {
+			return fmt.Errorf("failed to create router: %w", err)
+		}
+
+		config := server.DefaultConfig()
+		config.Address = c.String("address")
+		config.Port = c.String("port")
+		config.Socket = c.String("socket")
+		config.TLSCert = c.String("tls-cert")
+		config.TLSKey = c.String("tls-key")
+		config.SelfSigned = c.Bool("tls-self-signed")
+		config.ReadHeaderTimeout = c.Duration("read-header-timeout")
+		config.ReadTimeout = c.Duration("read-timeout")
+		config.WriteTimeout = c.Duration("write-timeout")
+		config.IdleTimeout = c.Duration("idle-timeout")
+		config.MaxHeaderBytes = c.Int("max-header-bytes")
+		config.ShutdownTimeout = c.Duration("shutdown-timeout")
+
+		ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			if err := watcher.Run(ctx); err != nil {
+				slog.Error("failed to watch the zettels, the pages will not refresh", "error", err)
+			}
+		}()
+
+		return server.New(config, r).Run(ctx)
+	},
+}
+
+var defaults = server.DefaultConfig()
+
+type serveOptions struct {
+	Dev bool
+	// Auth requires a token, or a session started with one, for every
+	// route but the login form and the public files.
+	Auth          bool
+	SessionSecret string
+}
+
+// newRouter registers the routes of the web server.
+func newRouter(db *database.Database, watcher *events.Watcher, opts serveOptions) (*router.Router, error) {
+	controller, err := controllers.NewController(db, watcher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller: %w", err)
+	}
+	apiController, err := api.NewController(db, watcher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api controller: %w", err)
+	}
+	tokenRepo, err := tq.New(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token repository: %w", err)
+	}
+	authenticator, err := auth.New(tokenRepo, []byte(opts.SessionSecret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authenticator: %w", err)
+	}
+	r := router.New()
+	authController := controllers.NewAuthController(authenticator, r)
+	workspaceRepo, err := wq.New(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace repository: %w", err)
+	}
+
+	var webAuth, apiAuth []middleware.Middleware
+	if opts.Auth {
+		webAuth = append(webAuth, authenticator.WithAuth(authController.Deny))
+		apiAuth = append(apiAuth, authenticator.WithAuth(api.Deny))
+	}
+
+	registry := newMetricsRegistry(db)
+	httpMetrics := metrics.NewHTTP(registry)
+
+	login := r.Group("/")
+	login.Use(httpMetrics.WithMetrics)
+	login.Use(middleware.WithCompression)
+	login.Use(middleware.WithMethods("GET", "POST"))
+	login.Use(middleware.WithURLs(r))
+	login.Use(middleware.WithCSRF(controllers.Deny))
+	login.Use(middleware.WithLayout)
+	login.Use(middleware.WithLogger)
+
+	login.HandleFunc("GET /login", authController.HandleLoginForm).Named("login")
+	login.HandleFunc("POST /login", authController.HandleLogin)
+	login.HandleFunc("POST /logout", authController.HandleLogout).Named("logout")
+
+	rr := r.Group("/")
+	rr.Use(httpMetrics.WithMetrics)
+	rr.Use(middleware.WithCompression)
+	rr.Use(middleware.WithDisableCache(opts.Dev))
+	rr.Use(middleware.WithMethods("GET", "POST", "DELETE"))
+	rr.Use(middleware.WithURLs(r))
+	rr.Use(middleware.WithCSRF(controllers.Deny))
+	rr.Use(middleware.WithLayout)
+	rr.Use(middleware.WithLogger)
+	rr.Use(webAuth...)
+
+	rr.HandleFunc("GET /{$}", controller.HandleHome).Named("home")
+	rr.HandleFunc("GET /workspaces", controller.HandleListWorkspaces).Named("workspaces")
+	rr.HandleFunc("GET /workspaces/create", controller.HandleCreateWorkspaceForm).Named("workspace.create")
+	rr.HandleFunc("POST /workspaces/create", controller.HandleCreateWorkspace)
+	rr.HandleFunc("GET /workspaces/edit/{id}", controller.HandleEditWorkspaceForm).Named("workspace.edit")
+	rr.HandleFunc("POST /workspaces/edit/{id}", controller.HandleEditWorkspace)
+	rr.HandleFunc("DELETE /workspaces/delete/{id}", controller.HandleDeleteWorkspace).Named("workspace.delete")
+	rr.HandleFunc("GET /workspaces/{id}", controller.HandleListZettels).Named("workspace")
+	rr.HandleFunc("GET /workspaces/{id}/zettels/tree", controller.HandleSequenceTree).Named("workspace.tree")
+	rr.HandleFunc("GET /workspaces/graph/{id}", controller.HandleGraph).Named("graph")
+	rr.HandleFunc("GET /workspaces/graph/stats/{id}", controller.HandleGraphStats).Named("graph.stats")
+
+	rr.HandleFunc("GET /workspaces/{id}/zettels/create", controller.HandleCreateZettelForm).Named("zettel.create")
+	rr.HandleFunc("POST /workspaces/{id}/zettels/create", controller.HandleCreateZettel)
+
+	rr.HandleFunc("GET /workspaces/{id}/zettels/{zettelId}", controller.HandleShowZettel).Named("zettel")
+	rr.HandleFunc("GET /workspaces/{id}/zettels/edit/{zettelId}", controller.HandleEditZettelForm).Named("zettel.edit")
+	rr.HandleFunc("POST /workspaces/{id}/zettels/edit/{zettelId}", controller.HandleEditZettel)
+	rr.HandleFunc("DELETE /workspaces/{id}/zettels/delete/{zettelId}", controller.HandleDeleteZettel).Named("zettel.delete")
+	rr.HandleFunc("GET /workspaces/{id}/zettels/rename/{zettelId}", controller.HandleRenameZettelForm).Named("zettel.rename")
+	rr.HandleFunc("POST /workspaces/{id}/zettels/rename/{zettelId}", controller.HandleRenameZettel)
+
+	// the pages of the paths no route matches, or not with the method of
+	// the request
+	fallback := r.Group("/")
+	fallback.Use(httpMetrics.WithMetrics)
+	fallback.Use(middleware.WithCompression)
+	fallback.Use(middleware.WithURLs(r))
+	fallback.Use(middleware.WithCSRF(controllers.Deny))
+	fallback.Use(middleware.WithLayout)
+	fallback.Use(middleware.WithLogger)
+
+	fallback.NotFound(controllers.NotFound)
+	fallback.MethodNotAllowed(controllers.MethodNotAllowed)
+
+	// the graph data is fetched by the graph page script, so it is kept out
+	// of the layout
+	data := r.Group("/")
+	data.Use(httpMetrics.WithMetrics)
+	data.Use(middleware.WithCompression)
+	data.Use(middleware.WithLogger)
+	data.Use(apiAuth...)
+
+	data.HandleFunc("GET /workspaces/graph/data/{id}", controller.HandleGraphData).Named("graph.data")
+
+	// the stream outlives the write timeout, and is kept out of the layout
+	// and the compression, which would hold the events back
+	stream := r.Group("/")
+	stream.Use(httpMetrics.WithMetrics)
+	stream.Use(middleware.WithURLs(r))
+	stream.Use(middleware.WithLogger)
+	stream.Use(webAuth...)
+
+	stream.HandleFunc("GET /events", watcher.Handler()).Named("events")
+
+	v1 := r.Group("/api/v1")
+	v1.Use(httpMetrics.WithMetrics)
+	v1.Use(middleware.WithCompression)
+	v1.Use(middleware.WithDisableCache(opts.Dev))
+	v1.Use(middleware.WithLogger)
+	v1.Use(apiAuth...)
+
+	v1.NotFound(api.NotFound)
+	v1.MethodNotAllowed(api.MethodNotAllowed)
+
+	v1.HandleFunc("GET /workspaces", apiController.HandleListWorkspaces).Describe(api.ListWorkspacesDoc)
+	v1.HandleFunc("POST /workspaces", apiController.HandleCreateWorkspace).Describe(api.CreateWorkspaceDoc)
+	v1.HandleFunc("GET /workspaces/{id}", apiController.HandleGetWorkspace).Describe(api.GetWorkspaceDoc)
+	v1.HandleFunc("PATCH /workspaces/{id}", apiController.HandleUpdateWorkspace).Describe(api.UpdateWorkspaceDoc)
+	v1.HandleFunc("DELETE /workspaces/{id}", apiController.HandleDeleteWorkspace).Describe(api.DeleteWorkspaceDoc)
+	v1.HandleFunc("GET /workspaces/{id}/zettels", apiController.HandleListWorkspaceZettels).Describe(api.ListWorkspaceZettelsDoc)
+
+	v1.HandleFunc("GET /zettels", apiController.HandleListZettels).Describe(api.ListZettelsDoc)
+	v1.HandleFunc("POST /zettels", apiController.HandleCreateZettel).Describe(api.CreateZettelDoc)
+	v1.HandleFunc("GET /zettels/{id}", apiController.HandleGetZettel).Describe(api.GetZettelDoc)
+	v1.HandleFunc("PATCH /zettels/{id}", apiController.HandleUpdateZettel).Describe(api.UpdateZettelDoc)
+	v1.HandleFunc("DELETE /zettels/{id}", apiController.HandleDeleteZettel).Describe(api.DeleteZettelDoc)
+	v1.HandleFunc("GET /zettels/{id}/links", apiController.HandleListLinks).Describe(api.ListLinksDoc)
+
+	v1.HandleFunc("GET /search", apiController.HandleSearch).Describe(api.SearchDoc)
+	v1.HandleFunc("GET /history", apiController.HandleListHistory).Describe(api.ListHistoryDoc)
+	v1.HandleFunc("POST /history", apiController.HandleAddToHistory).Describe(api.AddToHistoryDoc)
+
+	r.HandleFunc("GET /api/openapi.json", r.HandleOpenAPI(api.Info), httpMetrics.WithMetrics, middleware.WithCompression, middleware.WithLogger)
+
+	// probed by systemd or a load balancer, without a token and without
+	// logging every probe
+	probes := r.Group("/")
+	probes.Use(httpMetrics.WithMetrics)
+
+	probes.HandleFunc("GET /healthz", health.Handler(health.Database(db.DB.DB)))
+	probes.HandleFunc("GET /readyz", health.Handler(
+		health.Database(db.DB.DB),
+		health.Migrations(db.DB.DB),
+		health.Workspaces(workspaceRepo),
+	))
+
+	// scraped with a token like the API, e.g. bearer_token in the
+	// Prometheus scrape config
+	monitoring := r.Group("/")
+	monitoring.Use(httpMetrics.WithMetrics)
+	monitoring.Use(middleware.WithCompression)
+	monitoring.Use(apiAuth...)
+
+	monitoring.HandleFunc("GET /metrics", registry.Handler())
+
+	r.Handle("GET "+static.Prefix, static.Handler(),
+		httpMetrics.WithMetrics,
+		middleware.WithCompression,
+		middleware.WithDisableCache(opts.Dev),
+	)
+
+	return r, nil
+}
+
+// newMetricsRegistry returns the registry of the metrics endpoint, with the
+// query metrics and the size of the zettelkasten, read on each scrape.
+func newMetricsRegistry(db *database.Database) *metrics.Registry {
+	count := func(table string) func(ctx context.Context) (float64, error) {
+		return func(ctx context.Context) (float64, error) {
+			n, err := db.Count(ctx, table)
+			return float64(n), err
+		}
+	}
+
+	registry := metrics.NewRegistry()
+	registry.Register(
+		database.QueryDuration,
+		metrics.NewGaugeFunc("zet_zettels", "Number of zettels.", count("zettel")),
+		metrics.NewGaugeFunc("zet_workspaces", "Number of workspaces.", count("workspace")),
+		metrics.NewGaugeFunc("zet_links", "Number of links between zettels.", count("link")),
+		metrics.NewGaugeFunc("zet_database_size_bytes", "Size of the database files, with the write-ahead log.", func(ctx context.Context) (float64, error) {
+			size, err := db.Size()
+			return float64(size), err
+		}),
+	)
+	return registry
+}
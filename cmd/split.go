@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/fs"
+	"github.com/urfave/cli/v2"
+)
+
+var splitCommand = &cli.Command{
+	Name:      "split",
+	Usage:     "Splits a zettel at the chosen headings into new zettels",
+	ArgsUsage: "<id>",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "heading",
+			Usage: "Heading of a section to split off, asked interactively when omitted",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			return fmt.Errorf("usage: zet split <id>")
+		}
+
+		id, err := uuid.Parse(c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService()
+		if err != nil {
+			return err
+		}
+
+		headings := c.StringSlice("heading")
+		if len(headings) == 0 {
+			z, err := svc.FindZettel(c.Context, id)
+			if err != nil {
+				return err
+			}
+
+			sections := z.Sections()
+			if len(sections) == 0 {
+				return fmt.Errorf("%s has no headings to split at", z.Title())
+			}
+			for i, s := range sections {
+				fmt.Printf("%3d %s %s\n", i+1, strings.Repeat("#", s.Level), s.Heading)
+			}
+
+			answer := fs.Input("Sections to split off (e.g. 1,3): ")
+			for _, field := range strings.Split(answer, ",") {
+				field = strings.TrimSpace(field)
+				if field == "" {
+					continue
+				}
+				n, err := strconv.Atoi(field)
+				if err != nil || n < 1 || n > len(sections) {
+					return fmt.Errorf("invalid section %q", field)
+				}
+				headings = append(headings, sections[n-1].Heading)
+			}
+			if len(headings) == 0 {
+				return nil
+			}
+		}
+
+		parts, err := svc.Split(c.Context, id, headings)
+		if err != nil {
+			return err
+		}
+
+		for _, part := range parts {
+			fmt.Printf("Created %s %s\n", part.ID(), part.Title())
+		}
+		return nil
+	},
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/token"
+	tq "github.com/odas0r/zet/pkg/domain/token/sqlite"
+	"github.com/urfave/cli/v2"
+)
+
+var tokenCommand = &cli.Command{
+	Name:  "token",
+	Usage: "Manages the tokens that give access to the web server",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "create",
+			Usage:     "Creates a token and prints its secret, which is not shown again",
+			ArgsUsage: "<name>",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "scope",
+					Value: string(token.Read),
+					Usage: "What the token may do: read, or write to also change zettels and workspaces",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return fmt.Errorf("usage: zet token create [--scope read|write] <name>")
+				}
+				scope, err := token.ParseScope(c.String("scope"))
+				if err != nil {
+					return err
+				}
+
+				repo, err := newTokenRepository()
+				if err != nil {
+					return err
+				}
+				if _, err := repo.FindByName(c.Context, c.Args().First()); err == nil {
+					return token.ErrNameAlreadyExists
+				} else if !errors.Is(err, token.ErrTokenNotFound) {
+					return err
+				}
+
+				t, secret, err := token.New(c.Args().First(), scope)
+				if err != nil {
+					return err
+				}
+				if err := repo.Save(c.Context, t); err != nil {
+					return err
+				}
+
+				fmt.Println(secret)
+				return nil
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "Lists the tokens, without their secrets",
+			Action: func(c *cli.Context) error {
+				repo, err := newTokenRepository()
+				if err != nil {
+					return err
+				}
+				tokens, err := repo.FindAll(c.Context)
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tNAME\tSCOPE\tCREATED")
+				for _, t := range tokens {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.ID(), t.Name(), t.Scope(), t.Timestamp().Created.Local().Format(time.DateTime))
+				}
+				return w.Flush()
+			},
+		},
+		{
+			Name:      "revoke",
+			Usage:     "Revokes a token, ending the sessions started with it",
+			ArgsUsage: "<id|name>",
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return fmt.Errorf("usage: zet token revoke <id|name>")
+				}
+
+				repo, err := newTokenRepository()
+				if err != nil {
+					return err
+				}
+
+				var t token.Token
+				if id, err := uuid.Parse(c.Args().First()); err == nil {
+					t, err = repo.FindByID(c.Context, id)
+					if err != nil {
+						return err
+					}
+				} else if t, err = repo.FindByName(c.Context, c.Args().First()); err != nil {
+					return err
+				}
+
+				if err := repo.Delete(c.Context, t.ID()); err != nil {
+					return err
+				}
+				fmt.Printf("Revoked %s\n", t.Name())
+				return nil
+			},
+		},
+	},
+}
+
+func newTokenRepository() (token.Repository, error) {
+	db := database.New(database.Options{
+		URL: "zettel.db",
+	})
+	return tq.New(db)
+}
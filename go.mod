@@ -3,24 +3,29 @@ module github.com/odas0r/zet
 go 1.22
 
 require (
+	github.com/a-h/templ v0.2.707
 	github.com/google/uuid v1.6.0
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/microcosm-cc/bluemonday v1.0.27
 	github.com/muxit-studio/test v0.1.1
 	github.com/pressly/goose/v3 v3.20.0
+	github.com/qustavo/sqlhooks/v2 v2.1.0
 	github.com/urfave/cli/v2 v2.27.2
+	github.com/yuin/goldmark v1.7.8
 )
 
 require (
-	github.com/a-h/templ v0.2.707 // indirect
+	github.com/aymerick/douceur v0.2.0 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.4 // indirect
+	github.com/gorilla/css v1.0.1 // indirect
 	github.com/mfridman/interpolate v0.0.2 // indirect
 	github.com/muxit-studio/color v0.1.0 // indirect
 	github.com/muxit-studio/columnize v0.0.0-20200819155840-d363dedc9af5 // indirect
-	github.com/qustavo/sqlhooks/v2 v2.1.0 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/sethvargo/go-retry v0.2.4 // indirect
 	github.com/xrash/smetrics v0.0.0-20240312152122-5f08fbb34913 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
 )
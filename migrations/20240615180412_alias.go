@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAlias, downAlias)
+}
+
+func upAlias(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+create table alias (
+    zettel_id text not null,
+    name text not null,
+    created_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ')),
+
+    primary key (zettel_id, name),
+
+    foreign key (zettel_id) references zettel(id) on delete cascade
+) strict;
+
+create index alias_name_idx on alias (name);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAlias(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+drop table alias;
+`); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAliasUnique, downAliasUnique)
+}
+
+func upAliasUnique(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+-- an alias names a single zettel of a workspace; the workspaces of a zettel
+-- are in another table, so the triggers stand in for a unique index on
+-- (workspace_id, lower(name))
+create index alias_lower_name_idx on alias (lower(name));
+
+create trigger alias_unique_insert before insert on alias
+when exists (
+    select 1
+    from alias a
+    join workspace_zettel wa on wa.zettel_id = a.zettel_id
+    join workspace_zettel wn on wn.workspace_id = wa.workspace_id
+    where lower(a.name) = lower(new.name) and a.zettel_id != new.zettel_id and wn.zettel_id = new.zettel_id
+)
+begin
+    select raise(abort, 'alias already exists');
+end;
+
+create trigger alias_unique_workspace before insert on workspace_zettel
+when exists (
+    select 1
+    from alias n
+    join alias a on lower(a.name) = lower(n.name)
+    join workspace_zettel wa on wa.zettel_id = a.zettel_id
+    where n.zettel_id = new.zettel_id and a.zettel_id != new.zettel_id and wa.workspace_id = new.workspace_id
+)
+begin
+    select raise(abort, 'alias already exists');
+end;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAliasUnique(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+drop trigger alias_unique_workspace;
+drop trigger alias_unique_insert;
+drop index alias_lower_name_idx;
+`); err != nil {
+		return err
+	}
+	return nil
+}
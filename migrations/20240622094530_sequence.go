@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upSequence, downSequence)
+}
+
+func upSequence(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+-- folgezettel identifier, empty when the zettel is not part of a sequence
+alter table zettel add column sequence text not null default '';
+
+create index zettel_sequence_idx on zettel (sequence);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downSequence(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+drop index zettel_sequence_idx;
+alter table zettel drop column sequence;
+`); err != nil {
+		return err
+	}
+	return nil
+}
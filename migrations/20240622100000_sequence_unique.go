@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upSequenceUnique, downSequenceUnique)
+}
+
+func upSequenceUnique(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+-- a sequence identifier names a single zettel of a workspace; as with the
+-- aliases, the triggers stand in for a unique index on
+-- (workspace_id, sequence), leaving out the zettels without one
+create trigger zettel_sequence_unique_insert before insert on zettel
+when new.sequence != '' and exists (
+    select 1
+    from zettel z
+    join workspace_zettel wz on wz.zettel_id = z.id
+    join workspace_zettel wn on wn.workspace_id = wz.workspace_id
+    where z.sequence = new.sequence and z.id != new.id and wn.zettel_id = new.id
+)
+begin
+    select raise(abort, 'sequence identifier already exists in workspace');
+end;
+
+create trigger zettel_sequence_unique_update before update of sequence on zettel
+when new.sequence != '' and exists (
+    select 1
+    from zettel z
+    join workspace_zettel wz on wz.zettel_id = z.id
+    join workspace_zettel wn on wn.workspace_id = wz.workspace_id
+    where z.sequence = new.sequence and z.id != new.id and wn.zettel_id = new.id
+)
+begin
+    select raise(abort, 'sequence identifier already exists in workspace');
+end;
+
+create trigger zettel_sequence_unique_workspace before insert on workspace_zettel
+when exists (
+    select 1
+    from zettel n
+    join zettel z on z.sequence = n.sequence
+    join workspace_zettel wz on wz.zettel_id = z.id
+    where n.id = new.zettel_id and n.sequence != '' and z.id != n.id and wz.workspace_id = new.workspace_id
+)
+begin
+    select raise(abort, 'sequence identifier already exists in workspace');
+end;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downSequenceUnique(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+drop trigger zettel_sequence_unique_workspace;
+drop trigger zettel_sequence_unique_update;
+drop trigger zettel_sequence_unique_insert;
+`); err != nil {
+		return err
+	}
+	return nil
+}
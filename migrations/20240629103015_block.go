@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upBlock, downBlock)
+}
+
+func upBlock(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+create table block (
+    zettel_id text not null,
+    id text not null,
+    content text not null,
+    created_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ')),
+    updated_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ')),
+
+    primary key (zettel_id, id),
+
+    foreign key (zettel_id) references zettel(id) on delete cascade
+) strict;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downBlock(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+drop table block;
+`); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upToken, downToken)
+}
+
+func upToken(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+create table token (
+    id text primary key,
+    name text not null unique,
+    hash text not null unique,
+    scope text not null check (scope in ('read', 'write')),
+    created_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ')),
+    updated_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ'))
+) strict;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downToken(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+drop table token;
+`); err != nil {
+		return err
+	}
+	return nil
+}
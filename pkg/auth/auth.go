@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/token"
+	"github.com/odas0r/zet/pkg/router/middleware"
+)
+
+// SessionCookie holds the session of a user logged in through the login
+// form.
+const SessionCookie = "zet_session"
+
+// SessionDuration is how long a session lasts before logging in again.
+const SessionDuration = 7 * 24 * time.Hour
+
+var (
+	ErrUnauthenticated = errors.New("error: authentication required")
+	ErrInvalidToken    = errors.New("error: invalid token")
+	ErrForbidden       = errors.New("error: the token is read-only")
+)
+
+type contextKey struct{}
+
+// Authenticator checks the credentials of the requests: a bearer token in
+// the Authorization header, or a session cookie signed with its secret.
+// Sessions refer to the token used to log in, so revoking a token ends its
+// sessions too.
+type Authenticator struct {
+	tokens token.Repository
+	secret []byte
+}
+
+// New returns an authenticator signing sessions with the secret. Without
+// one, a random secret is used, and sessions end when the server stops.
+func New(tokens token.Repository, secret []byte) (*Authenticator, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &Authenticator{tokens: tokens, secret: secret}, nil
+}
+
+// FromContext returns the token a request was authenticated with.
+func FromContext(ctx context.Context) (token.Token, bool) {
+	t, ok := ctx.Value(contextKey{}).(token.Token)
+	return t, ok
+}
+
+// Authenticate returns the token of the request.
+func (a *Authenticator) Authenticate(r *http.Request) (token.Token, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		secret, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return token.Token{}, ErrInvalidToken
+		}
+		return a.find(r.Context(), secret)
+	}
+
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return token.Token{}, ErrUnauthenticated
+	}
+	id, err := a.verify(cookie.Value)
+	if err != nil {
+		return token.Token{}, err
+	}
+	t, err := a.tokens.FindByID(r.Context(), id)
+	if errors.Is(err, token.ErrTokenNotFound) {
+		return token.Token{}, ErrInvalidToken
+	}
+	return t, err
+}
+
+func (a *Authenticator) find(ctx context.Context, secret string) (token.Token, error) {
+	t, err := a.tokens.FindByHash(ctx, token.Hash(strings.TrimSpace(secret)))
+	if errors.Is(err, token.ErrTokenNotFound) {
+		return token.Token{}, ErrInvalidToken
+	}
+	return t, err
+}
+
+// WithAuth returns a middleware that lets through the requests
+// authenticated with a token whose scope allows them, and calls deny with
+// the reason for the others.
+func (a *Authenticator) WithAuth(deny func(w http.ResponseWriter, r *http.Request, err error)) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t, err := a.Authenticate(r)
+			if err != nil {
+				deny(w, r, err)
+				return
+			}
+			if !t.Scope().Allows(r.Method) {
+				deny(w, r, ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, t)))
+		})
+	}
+}
+
+// Login starts a session for the token with the given secret.
+func (a *Authenticator) Login(w http.ResponseWriter, r *http.Request, secret string) error {
+	t, err := a.find(r.Context(), secret)
+	if err != nil {
+		return err
+	}
+
+	expires := time.Now().Add(SessionDuration)
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    a.sign(t.ID(), expires),
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// Logout ends the session of the request.
+func (a *Authenticator) Logout(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// sign returns the value of a session cookie: the token id and the expiry,
+// followed by their signature.
+func (a *Authenticator) sign(id uuid.UUID, expires time.Time) string {
+	payload := fmt.Sprintf("%s.%d", id, expires.Unix())
+	return payload + "." + a.signature(payload)
+}
+
+func (a *Authenticator) verify(value string) (uuid.UUID, error) {
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		return uuid.Nil, ErrInvalidToken
+	}
+	payload, signature := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(a.signature(payload))) {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	id, expires, _ := strings.Cut(payload, ".")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().After(time.Unix(unix, 0)) {
+		return uuid.Nil, ErrUnauthenticated
+	}
+	return uuid.Parse(id)
+}
+
+func (a *Authenticator) signature(payload string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
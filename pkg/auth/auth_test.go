@@ -0,0 +1,141 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/auth"
+	"github.com/odas0r/zet/pkg/domain/token"
+)
+
+// memoryRepository keeps the tokens in memory, to test without a database.
+type memoryRepository struct {
+	tokens map[uuid.UUID]token.Token
+}
+
+func (m *memoryRepository) FindByID(ctx context.Context, id uuid.UUID) (token.Token, error) {
+	if t, ok := m.tokens[id]; ok {
+		return t, nil
+	}
+	return token.Token{}, token.ErrTokenNotFound
+}
+
+func (m *memoryRepository) FindByName(ctx context.Context, name string) (token.Token, error) {
+	for _, t := range m.tokens {
+		if t.Name() == name {
+			return t, nil
+		}
+	}
+	return token.Token{}, token.ErrTokenNotFound
+}
+
+func (m *memoryRepository) FindByHash(ctx context.Context, hash string) (token.Token, error) {
+	for _, t := range m.tokens {
+		if t.Hash() == hash {
+			return t, nil
+		}
+	}
+	return token.Token{}, token.ErrTokenNotFound
+}
+
+func (m *memoryRepository) FindAll(ctx context.Context) ([]token.Token, error) {
+	var tokens []token.Token
+	for _, t := range m.tokens {
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+func (m *memoryRepository) Save(ctx context.Context, t token.Token) error {
+	m.tokens[t.ID()] = t
+	return nil
+}
+
+func (m *memoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.tokens, id)
+	return nil
+}
+
+func TestAuthenticator_WithAuth(t *testing.T) {
+	repo := &memoryRepository{tokens: map[uuid.UUID]token.Token{}}
+	reader, readSecret, _ := token.New("reader", token.Read)
+	writer, writeSecret, _ := token.New("writer", token.Write)
+	repo.Save(context.Background(), reader)
+	repo.Save(context.Background(), writer)
+
+	a, err := auth.New(repo, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// session logs in with the secret and returns the session cookie
+	session := func(secret string) *http.Cookie {
+		rec := httptest.NewRecorder()
+		if err := a.Login(rec, httptest.NewRequest(http.MethodPost, "/login", nil), secret); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Result().Cookies()[0]
+	}
+	readSession := session(readSecret)
+	writeSession := session(writeSecret)
+	tampered := *readSession
+	tampered.Value = writeSession.Value + "x"
+
+	var denied error
+	handler := a.WithAuth(func(w http.ResponseWriter, r *http.Request, err error) {
+		denied = err
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.FromContext(r.Context()); !ok {
+			t.Error("expected the token in the context")
+		}
+	}))
+
+	type testCase struct {
+		test        string
+		method      string
+		bearer      string
+		cookie      *http.Cookie
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{test: "No credentials", method: http.MethodGet, expectedErr: auth.ErrUnauthenticated},
+		{test: "Read token reads", method: http.MethodGet, bearer: readSecret},
+		{test: "Read token does not write", method: http.MethodPost, bearer: readSecret, expectedErr: auth.ErrForbidden},
+		{test: "Write token writes", method: http.MethodDelete, bearer: writeSecret},
+		{test: "Unknown token", method: http.MethodGet, bearer: token.Prefix + "unknown", expectedErr: auth.ErrInvalidToken},
+		{test: "Read session reads", method: http.MethodGet, cookie: readSession},
+		{test: "Read session does not write", method: http.MethodPost, cookie: readSession, expectedErr: auth.ErrForbidden},
+		{test: "Write session writes", method: http.MethodPost, cookie: writeSession},
+		{test: "Tampered session", method: http.MethodGet, cookie: &tampered, expectedErr: auth.ErrInvalidToken},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			denied = nil
+			r := httptest.NewRequest(tc.method, "/", nil)
+			if tc.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.bearer)
+			}
+			if tc.cookie != nil {
+				r.AddCookie(tc.cookie)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			if denied != tc.expectedErr {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, denied)
+			}
+		})
+	}
+
+	t.Run("Revoked token ends its sessions", func(t *testing.T) {
+		repo.Delete(context.Background(), writer.ID())
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(writeSession)
+		if _, err := a.Authenticate(r); err != auth.ErrInvalidToken {
+			t.Errorf("expected error %v, got %v", auth.ErrInvalidToken, err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	wq "github.com/odas0r/zet/pkg/domain/workspace/sqlite"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/events"
+	"github.com/odas0r/zet/pkg/httperror"
+	"github.com/odas0r/zet/pkg/service"
+)
+
+// Pagination defaults, overridden with the limit and offset query
+// parameters.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidBody       = errors.New("error: invalid request body")
+	ErrInvalidID         = errors.New("error: invalid id")
+	ErrInvalidPagination = errors.New("error: limit and offset must be positive numbers")
+	ErrMissingQuery      = errors.New("error: missing search query")
+	ErrRouteNotFound     = shared.NotFound("error: no such route")
+)
+
+// Controller serves the JSON API, for clients that integrate with or extend
+// zet.
+type Controller struct {
+	workspaceRepo workspace.Repository
+	zettelRepo    zettel.Repository
+	service       *service.Service
+}
+
+// NewController returns the controller, whose changes to the zettels are
+// published by the watcher.
+func NewController(db *database.Database, watcher *events.Watcher) (*Controller, error) {
+	workspaceRepo, err := wq.New(db)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := zq.New(db)
+	if err != nil {
+		return nil, err
+	}
+	zettelRepo := watcher.Repository(repo)
+
+	return &Controller{
+		workspaceRepo: workspaceRepo,
+		zettelRepo:    zettelRepo,
+		service:       service.New(workspaceRepo, zettelRepo),
+	}, nil
+}
+
+type errorBody struct {
+	Error struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var body errorBody
+	body.Error.Status = statusOf(err)
+	body.Error.Message = err.Error()
+	if body.Error.Status == http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		body.Error.Message = httperror.Message(err)
+	}
+	writeJSON(w, body.Error.Status, body)
+}
+
+// statusOf maps the malformed requests to 400 Bad Request, and the other
+// errors as the web interface does.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidBody),
+		errors.Is(err, ErrInvalidID),
+		errors.Is(err, ErrInvalidPagination),
+		errors.Is(err, ErrMissingQuery):
+		return http.StatusBadRequest
+	}
+	return httperror.Status(err)
+}
+
+// NotFound answers the API requests whose path no route matches.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, ErrRouteNotFound)
+}
+
+// MethodNotAllowed answers the API requests whose route does not take their
+// method.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, httperror.ErrMethodNotAllowed)
+}
+
+// Deny answers the API requests that are not allowed.
+func Deny(w http.ResponseWriter, r *http.Request, err error) {
+	if statusOf(err) == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="zet"`)
+	}
+	writeError(w, r, err)
+}
+
+func decode(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return ErrInvalidBody
+	}
+	return nil
+}
+
+func pathID(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		return uuid.Nil, ErrInvalidID
+	}
+	return id, nil
+}
+
+// Page is a slice of a list, along with its position in the whole list.
+type Page[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+// pageQuery reads the page selected by the limit and offset query
+// parameters.
+func pageQuery(r *http.Request) (shared.Page, error) {
+	page := shared.Page{Limit: DefaultLimit}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return shared.Page{}, ErrInvalidPagination
+		}
+		page.Limit = min(n, MaxLimit)
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return shared.Page{}, ErrInvalidPagination
+		}
+		page.Offset = n
+	}
+	return page, nil
+}
+
+// paginate converts the items of the page, out of total, with toJSON.
+func paginate[T, J any](items []T, page shared.Page, total int, toJSON func(T) J) Page[J] {
+	p := Page[J]{
+		Data:       []J{},
+		Pagination: Pagination{Limit: page.Limit, Offset: page.Offset, Total: total},
+	}
+	for _, item := range items {
+		p.Data = append(p.Data, toJSON(item))
+	}
+	return p
+}
@@ -0,0 +1,127 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAPI_Errors(t *testing.T) {
+	wrk := createWorkspace(t)
+	title := uuid.NewString()
+	z := createZettel(t, wrk.ID, title, "content")
+	other := createZettel(t, wrk.ID, uuid.NewString(), "content")
+
+	type testCase struct {
+		test            string
+		method, target  string
+		body            any
+		expectedStatus  int
+		expectedMessage string
+	}
+
+	missing := uuid.NewString()
+	testCases := []testCase{
+		{
+			test:            "Zettel not found",
+			method:          http.MethodGet,
+			target:          "/api/v1/zettels/" + missing,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "error: zettel not found",
+		},
+		{
+			test:            "Workspace not found",
+			method:          http.MethodGet,
+			target:          "/api/v1/workspaces/" + missing,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "workspace not found",
+		},
+		{
+			test:            "Zettels of a workspace not found",
+			method:          http.MethodGet,
+			target:          "/api/v1/workspaces/" + missing + "/zettels",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "workspace not found",
+		},
+		{
+			test:            "Route not found",
+			method:          http.MethodGet,
+			target:          "/api/v1/missing",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "error: no such route",
+		},
+		{
+			test:           "Method not allowed",
+			method:         http.MethodPut,
+			target:         "/api/v1/zettels/" + z.ID,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			test:            "Invalid id",
+			method:          http.MethodGet,
+			target:          "/api/v1/zettels/1",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "error: invalid id",
+		},
+		{
+			test:            "Invalid body",
+			method:          http.MethodPost,
+			target:          "/api/v1/zettels",
+			body:            "{",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "error: invalid request body",
+		},
+		{
+			test:           "Invalid pagination",
+			method:         http.MethodGet,
+			target:         "/api/v1/workspaces/" + wrk.ID + "/zettels?limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			test:           "Missing search query",
+			method:         http.MethodGet,
+			target:         "/api/v1/search",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			test:           "Title taken",
+			method:         http.MethodPatch,
+			target:         "/api/v1/zettels/" + other.ID,
+			body:           map[string]string{"title": title},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			test:           "Found",
+			method:         http.MethodGet,
+			target:         "/api/v1/zettels/" + z.ID,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			rec := do(t, tc.method, tc.target, tc.body)
+			if rec.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, rec.Code, rec.Body)
+			}
+			if tc.expectedStatus < 400 {
+				return
+			}
+
+			var body struct {
+				Error struct {
+					Status  int    `json:"status"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			decode(t, rec, &body)
+			if body.Error.Status != tc.expectedStatus {
+				t.Errorf("expected the status %d in the body, got %d", tc.expectedStatus, body.Error.Status)
+			}
+			if tc.expectedMessage != "" && body.Error.Message != tc.expectedMessage {
+				t.Errorf("expected the message %q, got %q", tc.expectedMessage, body.Error.Message)
+			}
+		})
+	}
+}
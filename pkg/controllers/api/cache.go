@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/httpcache"
+)
+
+// notModified answers 304 when the client still has the representation of
+// the zettels.
+func notModified(w http.ResponseWriter, r *http.Request, zettels ...zettel.Zettel) bool {
+	return httpcache.NotModified(w, r, zettelsKey(r, zettels))
+}
+
+// zettelsKey returns the key of a response showing the zettels.
+func zettelsKey(r *http.Request, zettels []zettel.Zettel) *httpcache.Key {
+	key := httpcache.NewKey().Add(r.URL.RequestURI())
+	for _, z := range zettels {
+		key.Add(z.ID().String()).Modified(z.Timestamp().Updated)
+	}
+	return key
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/odas0r/zet/pkg/router"
+)
+
+// Info describes the API in its OpenAPI document.
+var Info = router.Info{
+	Title:       "zet",
+	Description: "JSON API to integrate with or extend a zettelkasten.",
+	Version:     "1",
+}
+
+var paginationParams = []router.Param{
+	{Name: "limit", Type: "integer", Description: "number of items, 50 by default and at most 200"},
+	{Name: "offset", Type: "integer", Description: "number of items to skip"},
+}
+
+// The descriptions of the routes, attached to them with Describe.
+var (
+	ListWorkspacesDoc = router.Doc{
+		Summary:  "List workspaces",
+		Tags:     []string{"workspaces"},
+		Query:    paginationParams,
+		Response: Page[workspaceJSON]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		Error:    errorBody{},
+	}
+	CreateWorkspaceDoc = router.Doc{
+		Summary:  "Create a workspace",
+		Tags:     []string{"workspaces"},
+		Request:  workspaceRequest{},
+		Status:   http.StatusCreated,
+		Response: workspaceJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity},
+		Error:    errorBody{},
+	}
+	GetWorkspaceDoc = router.Doc{
+		Summary:  "Get a workspace",
+		Tags:     []string{"workspaces"},
+		Response: workspaceJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		Error:    errorBody{},
+	}
+	UpdateWorkspaceDoc = router.Doc{
+		Summary:  "Change the path of a workspace",
+		Tags:     []string{"workspaces"},
+		Request:  workspaceRequest{},
+		Response: workspaceJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+		Error:    errorBody{},
+	}
+	DeleteWorkspaceDoc = router.Doc{
+		Summary: "Delete a workspace",
+		Tags:    []string{"workspaces"},
+		Status:  http.StatusNoContent,
+		Errors:  []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+		Error:   errorBody{},
+	}
+	ListWorkspaceZettelsDoc = router.Doc{
+		Summary:  "List the zettels of a workspace",
+		Tags:     []string{"workspaces"},
+		Query:    paginationParams,
+		Response: Page[zettelJSON]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		Error:    errorBody{},
+	}
+
+	ListZettelsDoc = router.Doc{
+		Summary:  "List zettels",
+		Tags:     []string{"zettels"},
+		Query:    paginationParams,
+		Response: Page[zettelJSON]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		Error:    errorBody{},
+	}
+	CreateZettelDoc = router.Doc{
+		Summary:  "Create a zettel, in the only workspace when none is given",
+		Tags:     []string{"zettels"},
+		Request:  createZettelRequest{},
+		Status:   http.StatusCreated,
+		Response: zettelJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		Error:    errorBody{},
+	}
+	GetZettelDoc = router.Doc{
+		Summary:  "Get a zettel",
+		Tags:     []string{"zettels"},
+		Response: zettelJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		Error:    errorBody{},
+	}
+	UpdateZettelDoc = router.Doc{
+		Summary:  "Update the given fields of a zettel",
+		Tags:     []string{"zettels"},
+		Request:  updateZettelRequest{},
+		Response: zettelJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		Error:    errorBody{},
+	}
+	DeleteZettelDoc = router.Doc{
+		Summary: "Delete a zettel",
+		Tags:    []string{"zettels"},
+		Status:  http.StatusNoContent,
+		Errors:  []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+		Error:   errorBody{},
+	}
+	ListLinksDoc = router.Doc{
+		Summary:  "List the outgoing links and backlinks of a zettel",
+		Tags:     []string{"zettels"},
+		Response: linksJSON{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		Error:    errorBody{},
+	}
+	SearchDoc = router.Doc{
+		Summary:  "Search zettels by title or alias",
+		Tags:     []string{"zettels"},
+		Query:    append([]router.Param{{Name: "q", Description: "search query", Required: true}}, paginationParams...),
+		Response: Page[zettelJSON]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		Error:    errorBody{},
+	}
+
+	ListHistoryDoc = router.Doc{
+		Summary:  "List the recently opened zettels",
+		Tags:     []string{"history"},
+		Query:    paginationParams,
+		Response: Page[visitJSON]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		Error:    errorBody{},
+	}
+	AddToHistoryDoc = router.Doc{
+		Summary: "Record that a zettel was opened",
+		Tags:    []string{"history"},
+		Request: historyRequest{},
+		Status:  http.StatusNoContent,
+		Errors:  []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+		Error:   errorBody{},
+	}
+)
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type historyRequest struct {
+	ZettelID uuid.UUID `json:"zettel_id"`
+}
+
+func (c *Controller) HandleListHistory(w http.ResponseWriter, r *http.Request) {
+	page, err := pageQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	visits, total, err := c.zettelRepo.FindHistory(r.Context(), page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(visits, page, total, newVisitJSON))
+}
+
+// HandleAddToHistory records that a client opened a zettel.
+func (c *Controller) HandleAddToHistory(w http.ResponseWriter, r *http.Request) {
+	var req historyRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, err := c.zettelRepo.FindByID(r.Context(), req.ZettelID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := c.zettelRepo.AddToHistory(r.Context(), req.ZettelID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
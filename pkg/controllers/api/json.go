@@ -0,0 +1,95 @@
+package api
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+type workspaceJSON struct {
+	ID        uuid.UUID   `json:"id"`
+	Path      string      `json:"path"`
+	Zettels   []uuid.UUID `json:"zettels"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func newWorkspaceJSON(w workspace.Workspace) workspaceJSON {
+	ids := w.ListZettelIDs()
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+
+	return workspaceJSON{
+		ID:        w.ID(),
+		Path:      w.Path(),
+		Zettels:   ids,
+		CreatedAt: w.Timestamp().Created,
+		UpdatedAt: w.Timestamp().Updated,
+	}
+}
+
+type zettelJSON struct {
+	ID        uuid.UUID   `json:"id"`
+	Title     string      `json:"title"`
+	Content   string      `json:"content"`
+	Kind      zettel.Kind `json:"kind"`
+	Sequence  string      `json:"sequence"`
+	Aliases   []string    `json:"aliases"`
+	Tags      []string    `json:"tags"`
+	Links     []uuid.UUID `json:"links"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func newZettelJSON(z zettel.Zettel) zettelJSON {
+	out := zettelJSON{
+		ID:        z.ID(),
+		Title:     z.Title(),
+		Content:   z.Content(),
+		Kind:      z.Kind(),
+		Sequence:  string(z.Sequence()),
+		Aliases:   z.Aliases(),
+		Tags:      z.Tags(),
+		Links:     []uuid.UUID{},
+		CreatedAt: z.Timestamp().Created,
+		UpdatedAt: z.Timestamp().Updated,
+	}
+	if out.Aliases == nil {
+		out.Aliases = []string{}
+	}
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	for _, link := range z.Links() {
+		out.Links = append(out.Links, link.To)
+	}
+	return out
+}
+
+// zettelSummaryJSON is a zettel referred to from another resource, without
+// its content.
+type zettelSummaryJSON struct {
+	ID    uuid.UUID   `json:"id"`
+	Title string      `json:"title"`
+	Kind  zettel.Kind `json:"kind"`
+}
+
+func newZettelSummaryJSON(z zettel.Zettel) zettelSummaryJSON {
+	return zettelSummaryJSON{ID: z.ID(), Title: z.Title(), Kind: z.Kind()}
+}
+
+type linksJSON struct {
+	Outgoing  []zettelSummaryJSON `json:"outgoing"`
+	Backlinks []zettelSummaryJSON `json:"backlinks"`
+}
+
+type visitJSON struct {
+	Zettel   zettelSummaryJSON `json:"zettel"`
+	OpenedAt time.Time         `json:"opened_at"`
+}
+
+func newVisitJSON(v zettel.Visit) visitJSON {
+	return visitJSON{Zettel: newZettelSummaryJSON(v.Zettel), OpenedAt: v.OpenedAt}
+}
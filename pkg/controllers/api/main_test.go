@@ -0,0 +1,115 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/controllers/api"
+	"github.com/odas0r/zet/pkg/database"
+	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/events"
+	"github.com/odas0r/zet/pkg/router"
+)
+
+var handler http.Handler
+
+func TestMain(m *testing.M) {
+	db := database.New(database.Options{
+		URL:                "../../../zettel.db",
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	})
+	repo, err := zq.New(db)
+	if err != nil {
+		log.Fatalf("Failed to set up zettel repository: %v", err)
+	}
+	// the watcher is not run, the changes are only notified
+	controller, err := api.NewController(db, events.NewWatcher(repo, events.DefaultInterval))
+	if err != nil {
+		log.Fatalf("Failed to set up controller: %v", err)
+	}
+
+	r := router.New()
+	v1 := r.Group("/api/v1")
+	v1.NotFound(api.NotFound)
+	v1.MethodNotAllowed(api.MethodNotAllowed)
+	v1.HandleFunc("POST /workspaces", controller.HandleCreateWorkspace)
+	v1.HandleFunc("GET /workspaces/{id}", controller.HandleGetWorkspace)
+	v1.HandleFunc("GET /workspaces/{id}/zettels", controller.HandleListWorkspaceZettels)
+	v1.HandleFunc("POST /zettels", controller.HandleCreateZettel)
+	v1.HandleFunc("GET /zettels/{id}", controller.HandleGetZettel)
+	v1.HandleFunc("PATCH /zettels/{id}", controller.HandleUpdateZettel)
+	v1.HandleFunc("GET /search", controller.HandleSearch)
+	handler = r
+
+	// Run the tests
+	m.Run()
+}
+
+// do serves the request, with the body encoded as JSON unless it is a
+// string.
+func do(t *testing.T, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if s, ok := body.(string); ok {
+		buf.WriteString(s)
+	} else if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, &buf))
+	return rec
+}
+
+// decode reads the JSON body of the response into v.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("expected a JSON body, got %v", err)
+	}
+}
+
+type workspaceBody struct {
+	ID string `json:"id"`
+}
+
+type zettelBody struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// createWorkspace creates a workspace through the API.
+func createWorkspace(t *testing.T) workspaceBody {
+	t.Helper()
+	rec := do(t, http.MethodPost, "/api/v1/workspaces", map[string]string{"path": t.TempDir()})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body)
+	}
+	var wrk workspaceBody
+	decode(t, rec, &wrk)
+	return wrk
+}
+
+// createZettel creates a zettel of the workspace through the API.
+func createZettel(t *testing.T, workspaceID, title, content string) zettelBody {
+	t.Helper()
+	rec := do(t, http.MethodPost, "/api/v1/zettels", map[string]string{
+		"title":        title,
+		"content":      content,
+		"kind":         "fleet",
+		"workspace_id": workspaceID,
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body)
+	}
+	var z zettelBody
+	decode(t, rec, &z)
+	return z
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/httpcache"
+)
+
+type workspaceRequest struct {
+	Path string `json:"path"`
+}
+
+func (c *Controller) HandleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	page, err := pageQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	workspaces, total, err := c.workspaceRepo.FindWorkspacePage(r.Context(), page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(workspaces, page, total, newWorkspaceJSON))
+}
+
+func (c *Controller) HandleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	var req workspaceRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	wrk, err := workspace.New(req.Path)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := c.workspaceRepo.Save(r.Context(), wrk); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newWorkspaceJSON(wrk))
+}
+
+func (c *Controller) HandleGetWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	wrk, err := c.workspaceRepo.FindWorkspaceByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newWorkspaceJSON(wrk))
+}
+
+func (c *Controller) HandleUpdateWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var req workspaceRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	wrk, err := c.workspaceRepo.FindWorkspaceByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	// validate the new path the same way a new workspace is
+	if _, err := workspace.New(req.Path); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	wrk.SetPath(req.Path)
+	if err := c.workspaceRepo.Save(r.Context(), wrk); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newWorkspaceJSON(wrk))
+}
+
+func (c *Controller) HandleDeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, err := c.workspaceRepo.FindWorkspaceByID(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := c.workspaceRepo.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Controller) HandleListWorkspaceZettels(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, err := c.workspaceRepo.FindWorkspaceByID(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := pageQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	zettels, total, err := c.zettelRepo.FindPageInWorkspace(r.Context(), id, page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	// the total changes with the zettels of the other pages
+	if httpcache.NotModified(w, r, zettelsKey(r, zettels).Add(strconv.Itoa(total))) {
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(zettels, page, total, newZettelJSON))
+}
@@ -0,0 +1,83 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAPI_ListWorkspaceZettels(t *testing.T) {
+	wrk := createWorkspace(t)
+	// the zettels are listed oldest first, and the ones created in the same
+	// millisecond by id, so the pages are compared with the whole list
+	created := map[string]bool{}
+	for range 3 {
+		created[createZettel(t, wrk.ID, uuid.NewString(), "content").ID] = true
+	}
+	var zettels []zettelBody
+
+	type testCase struct {
+		test           string
+		query          string
+		expectedLimit  int
+		expectedOffset int
+		// the zettels of the page, from and to the given ones of the list
+		from, to int
+	}
+
+	testCases := []testCase{
+		{test: "Default page", expectedLimit: 50, from: 0, to: 3},
+		{test: "First page", query: "?limit=2", expectedLimit: 2, from: 0, to: 2},
+		{test: "Last page", query: "?limit=2&offset=2", expectedLimit: 2, expectedOffset: 2, from: 2, to: 3},
+		{test: "Past the end", query: "?offset=3", expectedLimit: 50, expectedOffset: 3, from: 3, to: 3},
+		{test: "Limit above the maximum", query: "?limit=1000", expectedLimit: 200, from: 0, to: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			rec := do(t, http.MethodGet, fmt.Sprintf("/api/v1/workspaces/%s/zettels%s", wrk.ID, tc.query), nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+			}
+
+			var page struct {
+				Data       []zettelBody `json:"data"`
+				Pagination struct {
+					Limit  int `json:"limit"`
+					Offset int `json:"offset"`
+					Total  int `json:"total"`
+				} `json:"pagination"`
+			}
+			decode(t, rec, &page)
+			if zettels == nil {
+				zettels = page.Data
+				if len(zettels) != len(created) {
+					t.Fatalf("expected %d zettels, got %d", len(created), len(zettels))
+				}
+				for _, z := range zettels {
+					if !created[z.ID] {
+						t.Fatalf("expected the zettels of the workspace, got %s", z.ID)
+					}
+				}
+			}
+
+			if page.Data == nil {
+				t.Error("expected the data to be a list")
+			}
+			if page.Pagination.Limit != tc.expectedLimit || page.Pagination.Offset != tc.expectedOffset || page.Pagination.Total != len(created) {
+				t.Errorf("expected limit %d, offset %d and total %d, got %+v", tc.expectedLimit, tc.expectedOffset, len(created), page.Pagination)
+			}
+			expected := zettels[tc.from:tc.to]
+			if len(page.Data) != len(expected) {
+				t.Fatalf("expected %d zettels, got %d", len(expected), len(page.Data))
+			}
+			for i := range page.Data {
+				if page.Data[i].ID != expected[i].ID {
+					t.Errorf("expected %s at %d, got %s", expected[i].ID, i, page.Data[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/service"
+)
+
+type createZettelRequest struct {
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Kind        string    `json:"kind"`
+	Sequence    string    `json:"sequence"`
+	Aliases     []string  `json:"aliases"`
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+}
+
+// updateZettelRequest holds the fields to change; the ones left out keep
+// their value. A new title renames the zettel as zet mv does.
+type updateZettelRequest struct {
+	Title    *string   `json:"title"`
+	Content  *string   `json:"content"`
+	Kind     *string   `json:"kind"`
+	Sequence *string   `json:"sequence"`
+	Aliases  *[]string `json:"aliases"`
+}
+
+func (c *Controller) HandleListZettels(w http.ResponseWriter, r *http.Request) {
+	page, err := pageQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	zettels, total, err := c.zettelRepo.FindPage(r.Context(), page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(zettels, page, total, newZettelJSON))
+}
+
+func (c *Controller) HandleCreateZettel(w http.ResponseWriter, r *http.Request) {
+	var req createZettelRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	z, err := zettel.New(req.Title, req.Content, zettel.Kind(req.Kind))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if req.Sequence != "" {
+		seq, err := zettel.ParseSequence(req.Sequence)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		z.SetSequence(seq)
+	}
+	if err := z.ReplaceAliases(req.Aliases); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	workspaceID := req.WorkspaceID
+	if workspaceID == uuid.Nil {
+		wrk, err := c.service.DefaultWorkspace(r.Context(), uuid.Nil)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		workspaceID = wrk.ID()
+	}
+	if err := c.service.CreateZettel(r.Context(), z, workspaceID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	// the links are resolved on save, so the stored zettel is returned
+	created, err := c.zettelRepo.FindByID(r.Context(), z.ID())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newZettelJSON(created))
+}
+
+func (c *Controller) HandleGetZettel(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	z, err := c.zettelRepo.FindByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if notModified(w, r, z) {
+		return
+	}
+	writeJSON(w, http.StatusOK, newZettelJSON(z))
+}
+
+func (c *Controller) HandleUpdateZettel(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var req updateZettelRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	z, err := c.zettelRepo.FindByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// a new title renames the zettel, rewriting the references to the old
+	// one, so it is planned before anything is saved
+	var rename *service.Rename
+	if req.Title != nil && *req.Title != z.Title() {
+		plan, err := c.service.PlanRename(r.Context(), id, *req.Title)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		rename = &plan
+	}
+	if req.Content != nil {
+		if *req.Content == "" {
+			writeError(w, r, zettel.ErrMissingValues)
+			return
+		}
+		z.SetBody(*req.Content)
+	}
+	if req.Kind != nil {
+		kind := zettel.Kind(*req.Kind)
+		if kind != zettel.Permanent && kind != zettel.Fleet {
+			writeError(w, r, zettel.ErrInvalidZettelKind)
+			return
+		}
+		z.SetKind(kind)
+	}
+	if req.Sequence != nil {
+		var seq zettel.Sequence
+		if *req.Sequence != "" {
+			if seq, err = zettel.ParseSequence(*req.Sequence); err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+		z.SetSequence(seq)
+	}
+	aliases := z.Aliases()
+	if req.Aliases != nil {
+		aliases = *req.Aliases
+	}
+	// the aliases are merged again with the ones of the front matter, which
+	// may have changed with the content
+	if err := z.ReplaceAliases(aliases); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// links resolve to the zettels of its first workspace first, as in zet open
+	workspaceID := uuid.Nil
+	if wrk, err := c.service.DefaultWorkspace(r.Context(), z.ID()); err == nil {
+		workspaceID = wrk.ID()
+	}
+	if rename != nil {
+		err = c.service.SaveRenamedZettel(r.Context(), z, workspaceID, *rename)
+	} else {
+		err = c.service.SaveZettel(r.Context(), z, workspaceID)
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	updated, err := c.zettelRepo.FindByID(r.Context(), z.ID())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newZettelJSON(updated))
+}
+
+func (c *Controller) HandleDeleteZettel(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := c.zettelRepo.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListLinks returns the zettels a zettel links to and the ones
+// linking to it. Links follow the wiki-links in the content, so they are
+// changed by updating the content.
+func (c *Controller) HandleListLinks(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	z, err := c.zettelRepo.FindByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	outgoing, backlinks, err := c.service.Linked(r.Context(), z)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	links := linksJSON{Outgoing: []zettelSummaryJSON{}, Backlinks: []zettelSummaryJSON{}}
+	for _, to := range outgoing {
+		links.Outgoing = append(links.Outgoing, newZettelSummaryJSON(to))
+	}
+	for _, from := range backlinks {
+		links.Backlinks = append(links.Backlinks, newZettelSummaryJSON(from))
+	}
+	writeJSON(w, http.StatusOK, links)
+}
+
+func (c *Controller) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, r, ErrMissingQuery)
+		return
+	}
+
+	page, err := pageQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	zettels, total, err := c.zettelRepo.SearchPageByName(r.Context(), query, page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(zettels, page, total, newZettelJSON))
+}
@@ -0,0 +1,54 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAPI_UpdateZettelTitle(t *testing.T) {
+	wrk := createWorkspace(t)
+	z := createZettel(t, wrk.ID, uuid.NewString(), "content")
+	referencing := createZettel(t, wrk.ID, uuid.NewString(), "see [["+z.Title+"]]")
+
+	title := uuid.NewString()
+	rec := do(t, http.MethodPatch, "/api/v1/zettels/"+z.ID, map[string]string{"title": title})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var updated zettelBody
+	decode(t, rec, &updated)
+	if updated.Title != title {
+		t.Errorf("expected the title %q, got %q", title, updated.Title)
+	}
+
+	// the references to the old title follow the zettel
+	rec = do(t, http.MethodGet, "/api/v1/zettels/"+referencing.ID, nil)
+	var got zettelBody
+	decode(t, rec, &got)
+	if !strings.Contains(got.Content, "[["+title+"]]") {
+		t.Errorf("expected the reference to be renamed, got %q", got.Content)
+	}
+}
+
+func TestAPI_UpdateZettelTitleTaken(t *testing.T) {
+	wrk := createWorkspace(t)
+	alias := uuid.NewString()
+	createZettel(t, wrk.ID, uuid.NewString(), "---\naliases: ["+alias+"]\n---\ncontent")
+	z := createZettel(t, wrk.ID, uuid.NewString(), "content")
+
+	rec := do(t, http.MethodPatch, "/api/v1/zettels/"+z.ID, map[string]string{"title": alias, "content": "edited"})
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusConflict, rec.Code, rec.Body)
+	}
+
+	// the other changes are saved with the rename only
+	rec = do(t, http.MethodGet, "/api/v1/zettels/"+z.ID, nil)
+	var got zettelBody
+	decode(t, rec, &got)
+	if got.Title != z.Title || got.Content != z.Content {
+		t.Errorf("expected the zettel unchanged, got %q: %q", got.Title, got.Content)
+	}
+}
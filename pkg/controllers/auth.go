@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/odas0r/zet/pkg/auth"
+	"github.com/odas0r/zet/pkg/view"
+)
+
+// AuthController serves the login form that starts the session of the web
+// interface.
+type AuthController struct {
+	auth *auth.Authenticator
+	urls view.URLs
+}
+
+// NewAuthController returns the controller, which redirects to the login
+// route of the urls.
+func NewAuthController(a *auth.Authenticator, urls view.URLs) *AuthController {
+	return &AuthController{auth: a, urls: urls}
+}
+
+func (c *AuthController) HandleLoginForm(w http.ResponseWriter, r *http.Request) {
+	component := view.LoginForm(localPath(r.URL.Query().Get("next")), "")
+	templ.Handler(component).ServeHTTP(w, r)
+}
+
+func (c *AuthController) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	next := localPath(r.FormValue("next"))
+	if err := c.auth.Login(w, r, r.FormValue("token")); err != nil {
+		component := view.LoginForm(next, err.Error())
+		templ.Handler(component, templ.WithStatus(http.StatusUnauthorized)).ServeHTTP(w, r)
+		return
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+func (c *AuthController) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	c.auth.Logout(w)
+	login, err := c.urls.URL("login")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	http.Redirect(w, r, login, http.StatusSeeOther)
+}
+
+// Deny answers the requests of the web interface that are not allowed:
+// without a session they go to the login form, which brings them back.
+func (c *AuthController) Deny(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, auth.ErrForbidden) {
+		renderError(w, r, err)
+		return
+	}
+
+	login, err := c.urls.URL("login")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	login += "?next=" + url.QueryEscape(r.URL.RequestURI())
+	if r.Header.Get("HX-Request") == "true" {
+		// htmx would swap the login form into the page, so the whole page
+		// goes to it instead
+		w.Header().Set("HX-Redirect", login)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	http.Redirect(w, r, login, http.StatusSeeOther)
+}
+
+// localPath returns the path to go to after logging in, which must be on
+// this server.
+func localPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
@@ -0,0 +1,90 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/auth"
+	"github.com/odas0r/zet/pkg/controllers"
+	"github.com/odas0r/zet/pkg/router"
+	"github.com/odas0r/zet/pkg/view"
+)
+
+func TestAuthController_Deny(t *testing.T) {
+	handle := func(w http.ResponseWriter, r *http.Request) {}
+	r := router.New()
+	r.HandleFunc("GET /{$}", handle).Named("home")
+	r.HandleFunc("GET /signin", handle).Named("login")
+	c := controllers.NewAuthController(nil, r)
+
+	type testCase struct {
+		test             string
+		err              error
+		urls             view.URLs
+		htmx             bool
+		expectedStatus   int
+		expectedLocation string
+		expectedBody     string
+	}
+
+	testCases := []testCase{
+		{
+			test:             "Unauthenticated",
+			err:              auth.ErrUnauthenticated,
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/signin?next=%2Fevents",
+		},
+		{
+			test:             "Unauthenticated with htmx",
+			err:              auth.ErrUnauthenticated,
+			htmx:             true,
+			expectedStatus:   http.StatusUnauthorized,
+			expectedLocation: "/signin?next=%2Fevents",
+		},
+		{
+			test:           "Forbidden",
+			err:            auth.ErrForbidden,
+			urls:           r,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `href="/"`,
+		},
+		// the stream of events used to be served without the routes, so
+		// that the error page panicked
+		{
+			test:           "Forbidden without the routes",
+			err:            auth.ErrForbidden,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `href="#"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			if tc.urls != nil {
+				req = req.WithContext(view.WithURLs(req.Context(), tc.urls))
+			}
+			if tc.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+			rec := httptest.NewRecorder()
+			c.Deny(rec, req, tc.err)
+
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, rec.Code)
+			}
+			location := rec.Header().Get("Location")
+			if tc.htmx {
+				location = rec.Header().Get("HX-Redirect")
+			}
+			if location != tc.expectedLocation {
+				t.Errorf("expected to go to %q, got %q", tc.expectedLocation, location)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, tc.expectedBody) {
+				t.Errorf("expected the body to contain %s, got %q", tc.expectedBody, body)
+			}
+		})
+	}
+}
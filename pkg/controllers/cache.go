@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/httpcache"
+	"github.com/odas0r/zet/pkg/view"
+)
+
+// notModified answers 304 when the client still has the page showing the
+// zettels.
+func notModified(w http.ResponseWriter, r *http.Request, zettels ...zettel.Zettel) bool {
+	key := pageKey(w, r)
+	for _, z := range zettels {
+		key.Add(z.ID().String()).Modified(z.Timestamp().Updated)
+	}
+	return httpcache.NotModified(w, r, key)
+}
+
+// pageKey returns the key of the page requested. A page differs for htmx
+// requests, which get it without the layout, and carries the CSRF token of
+// the client.
+func pageKey(w http.ResponseWriter, r *http.Request) *httpcache.Key {
+	w.Header().Add("Vary", "HX-Request")
+	return httpcache.NewKey().Add(r.URL.RequestURI(), r.Header.Get("HX-Request"), view.CSRFToken(r.Context()))
+}
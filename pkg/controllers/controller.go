@@ -1,49 +1,65 @@
 package controllers
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/a-h/templ"
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3" // Import the SQLite driver
 	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/shared"
 	"github.com/odas0r/zet/pkg/domain/workspace"
 	wq "github.com/odas0r/zet/pkg/domain/workspace/sqlite"
 	"github.com/odas0r/zet/pkg/domain/zettel"
 	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/events"
+	"github.com/odas0r/zet/pkg/graph"
+	"github.com/odas0r/zet/pkg/httpcache"
+	"github.com/odas0r/zet/pkg/httperror"
+	"github.com/odas0r/zet/pkg/markdown"
+	"github.com/odas0r/zet/pkg/service"
 	"github.com/odas0r/zet/pkg/view"
 )
 
+var (
+	errInvalidDepth = shared.Invalid("error: depth must be a whole number of links")
+	errInvalidRoot  = shared.Invalid("error: root must be the id of a zettel")
+)
+
 type Controller struct {
 	workspaceRepo workspace.Repository
 	zettelRepo    zettel.Repository
+	service       *service.Service
 }
 
-func NewController(db *database.Database) (*Controller, error) {
+// NewController returns the controller, whose changes to the zettels are
+// published by the watcher.
+func NewController(db *database.Database, watcher *events.Watcher) (*Controller, error) {
 	workspaceRepo, err := wq.New(db)
 	if err != nil {
 		return nil, err
 	}
-	zettelRepo, err := zq.New(db)
+	repo, err := zq.New(db)
 	if err != nil {
 		return nil, err
 	}
+	zettelRepo := watcher.Repository(repo)
 
 	return &Controller{
 		workspaceRepo: workspaceRepo,
 		zettelRepo:    zettelRepo,
+		service:       service.New(workspaceRepo, zettelRepo),
 	}, nil
 }
 
-func (c *Controller) renderError(w http.ResponseWriter, r *http.Request, err error) {
-	component := view.ErrorMessage(err.Error())
-	templ.Handler(component).ServeHTTP(w, r)
-}
-
 func (c *Controller) HandleHome(w http.ResponseWriter, r *http.Request) {
-	workspaces, err := c.workspaceRepo.FindAllWorkspaces()
+	workspaces, err := c.workspaceRepo.FindAllWorkspaces(r.Context())
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 
@@ -58,9 +74,9 @@ func (c *Controller) HandleHome(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *Controller) HandleListWorkspaces(w http.ResponseWriter, r *http.Request) {
-	workspaces, err := c.workspaceRepo.FindAllWorkspaces()
+	workspaces, err := c.workspaceRepo.FindAllWorkspaces(r.Context())
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 	component := view.ListWorkspaces(workspaces)
@@ -76,26 +92,25 @@ func (c *Controller) HandleCreateWorkspace(w http.ResponseWriter, r *http.Reques
 	path := r.FormValue("path")
 	wrk, err := workspace.New(path)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
-	if err := c.workspaceRepo.Save(wrk); err != nil {
-		c.renderError(w, r, err)
+	if err := c.workspaceRepo.Save(r.Context(), wrk); err != nil {
+		renderError(w, r, err)
 		return
 	}
 	c.HandleListWorkspaces(w, r)
 }
 
 func (c *Controller) HandleEditWorkspaceForm(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	wrkID, err := uuid.Parse(id)
+	wrkID, err := pathID(r, "id")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
-	wrk, err := c.workspaceRepo.FindWorkspaceByID(wrkID)
+	wrk, err := c.workspaceRepo.FindWorkspaceByID(r.Context(), wrkID)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 	component := view.EditWorkspaceForm(wrk)
@@ -103,77 +118,205 @@ func (c *Controller) HandleEditWorkspaceForm(w http.ResponseWriter, r *http.Requ
 }
 
 func (c *Controller) HandleEditWorkspace(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	wrkID, err := uuid.Parse(id)
+	wrkID, err := pathID(r, "id")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
-	wrk, err := c.workspaceRepo.FindWorkspaceByID(wrkID)
+	wrk, err := c.workspaceRepo.FindWorkspaceByID(r.Context(), wrkID)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 	wrk.SetPath(r.FormValue("path"))
-	if err := c.workspaceRepo.Save(wrk); err != nil {
-		c.renderError(w, r, err)
+	if err := c.workspaceRepo.Save(r.Context(), wrk); err != nil {
+		renderError(w, r, err)
 		return
 	}
 	c.HandleListWorkspaces(w, r)
 }
 
 func (c *Controller) HandleDeleteWorkspace(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	wrkID, err := uuid.Parse(id)
+	wrkID, err := pathID(r, "id")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
-	if err := c.workspaceRepo.Delete(wrkID); err != nil {
-		c.renderError(w, r, err)
+	if err := c.workspaceRepo.Delete(r.Context(), wrkID); err != nil {
+		renderError(w, r, err)
 		return
 	}
 	c.HandleListWorkspaces(w, r)
 }
 
 func (c *Controller) HandleListZettels(w http.ResponseWriter, r *http.Request) {
-	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	workspaceID, err := pathID(r, "id")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 
-	zettels, err := c.zettelRepo.FindZettelsByWorkspaceID(workspaceID)
+	zettels, err := c.zettelRepo.FindZettelsByWorkspaceID(r.Context(), workspaceID)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
+		return
+	}
+	if notModified(w, r, zettels...) {
 		return
 	}
 	component := view.ListZettels(workspaceID, zettels)
 	templ.Handler(component).ServeHTTP(w, r)
 }
 
-func (c *Controller) HandleCreateZettelForm(w http.ResponseWriter, r *http.Request) {
-	workspaceIDStr := r.PathValue("id")
-	workspaceID, err := uuid.Parse(workspaceIDStr)
+func (c *Controller) HandleSequenceTree(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 
-	component := view.CreateZettelForm(workspaceID)
+	tree, err := c.service.SequenceTree(r.Context(), workspaceID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	component := view.SequenceTree(workspaceID, tree)
 	templ.Handler(component).ServeHTTP(w, r)
 }
 
-func (c *Controller) HandleCreateZettel(w http.ResponseWriter, r *http.Request) {
-	workspaceID, err := uuid.Parse(r.PathValue("id"))
+func (c *Controller) HandleGraphStats(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	g, err := c.service.Graph(r.Context())
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	g = g.Filter(graph.Filter{WorkspaceID: workspaceID})
+
+	component := view.GraphStats(workspaceID, view.GraphStatsData{
+		Stats:    g.Stats(),
+		PageRank: top(g.PageRank(graph.DefaultDamping, graph.DefaultIterations), 10),
+		Degree:   top(g.Degree(), 10),
+		Islands:  g.Islands(),
+	})
+	templ.Handler(component).ServeHTTP(w, r)
+}
+
+func (c *Controller) HandleGraph(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	g, err := c.service.Graph(r.Context())
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
+	g = g.Filter(graph.Filter{WorkspaceID: workspaceID})
 
-	workspace, err := c.workspaceRepo.FindWorkspaceByID(workspaceID)
+	root, depth, err := graphQuery(r)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
+		return
+	}
+	if root == uuid.Nil {
+		// start from the most central zettel, so a depth has somewhere to
+		// grow from
+		if ranks := g.PageRank(graph.DefaultDamping, graph.DefaultIterations); len(ranks) > 0 {
+			root = ranks[0].Node.ID
+		}
+	}
+
+	component := view.Graph(workspaceID, view.GraphData{
+		Nodes: g.Nodes,
+		Root:  root,
+		Depth: depth,
+	})
+	templ.Handler(component).ServeHTTP(w, r)
+}
+
+// HandleGraphData serves the nodes and links of the workspace graph as JSON,
+// limited to the neighbourhood of the root when a depth is given.
+func (c *Controller) HandleGraphData(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, httperror.Message(err), httperror.Status(err))
+		return
+	}
+	root, depth, err := graphQuery(r)
+	if err != nil {
+		http.Error(w, httperror.Message(err), httperror.Status(err))
+		return
+	}
+
+	g, err := c.service.Graph(r.Context())
+	if err != nil {
+		http.Error(w, httperror.Message(err), httperror.Status(err))
+		return
+	}
+	g = g.Filter(graph.Filter{WorkspaceID: workspaceID})
+	if root != uuid.Nil && depth > 0 {
+		g = g.Neighbourhood(root, depth)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := g.WriteJSON(w); err != nil {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+}
+
+// graphQuery reads the root zettel and the depth of the graph from the
+// query string. A depth of 0 shows the whole graph.
+func graphQuery(r *http.Request) (uuid.UUID, int, error) {
+	var root uuid.UUID
+	if value := r.URL.Query().Get("root"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return uuid.Nil, 0, errInvalidRoot
+		}
+		root = id
+	}
+
+	depth := 0
+	if value := r.URL.Query().Get("depth"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return uuid.Nil, 0, errInvalidDepth
+		}
+		depth = n
+	}
+	return root, depth, nil
+}
+
+func top(scores []graph.Score, n int) []graph.Score {
+	if len(scores) > n {
+		return scores[:n]
+	}
+	return scores
+}
+
+func (c *Controller) HandleCreateZettelForm(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	component := view.CreateZettelForm(workspaceID)
+	templ.Handler(component).ServeHTTP(w, r)
+}
+
+func (c *Controller) HandleCreateZettel(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
 		return
 	}
 
@@ -183,42 +326,112 @@ func (c *Controller) HandleCreateZettel(w http.ResponseWriter, r *http.Request)
 
 	zett, err := zettel.New(title, content, zettel.Kind(kind))
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
+		return
+	}
+	if err := zett.ReplaceAliases(nil); err != nil {
+		renderError(w, r, err)
 		return
 	}
-	if err := c.zettelRepo.Save(zett); err != nil {
-		c.renderError(w, r, err)
+	if err := c.service.CreateZettel(r.Context(), zett, workspaceID); err != nil {
+		renderError(w, r, err)
 		return
 	}
 
-	// Add the zettel to the workspace
-	workspace.AddZettel(zett.ID())
+	c.HandleListZettels(w, r)
+}
 
-	// Save the workspace
-	if err := c.workspaceRepo.Save(workspace); err != nil {
-		c.renderError(w, r, err)
+func (c *Controller) HandleShowZettel(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
 		return
 	}
 
-	c.HandleListZettels(w, r)
+	zetID, err := pathID(r, "zettelId")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	zet, err := c.zettelRepo.FindByID(r.Context(), zetID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	// the page refreshing itself on a change of the zettels is no visit,
+	// see view.live
+	if r.Header.Get("HX-Trigger") != "zettel" {
+		if err := c.service.Visit(r.Context(), zet.ID()); err != nil {
+			renderError(w, r, err)
+			return
+		}
+	}
+
+	// the page changes with more than the zettels it shows: the embedded
+	// zettels embed others in turn, and a broken link resolves once a zettel
+	// takes its title. So it changes with any zettel, or with the number of
+	// zettels when one is deleted.
+	updated, count, err := c.zettelRepo.FindVersion(r.Context())
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	key := pageKey(w, r).Add(strconv.Itoa(count)).Modified(updated)
+	if httpcache.NotModified(w, r, key) {
+		return
+	}
+
+	outgoing, backlinks, err := c.service.Linked(r.Context(), zet)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	blockBacklinks, err := c.service.BlockBacklinks(r.Context(), zet, workspaceID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	content := c.service.Transclude(r.Context(), zet, workspaceID, markdown.WrapHTML)
+	body, err := markdown.Render(content, func(ref zettel.Reference) (string, bool) {
+		id, ok := c.service.Resolve(r.Context(), workspaceID, ref.Title)
+		if !ok {
+			return "", false
+		}
+		if blockID := ref.BlockID(); blockID != "" {
+			if _, err := c.zettelRepo.FindBlock(r.Context(), id, blockID); err != nil {
+				return "", false
+			}
+		}
+		return fmt.Sprintf("/workspaces/%s/zettels/%s", workspaceID, id), true
+	})
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	component := view.ShowZettel(workspaceID, zet, body, outgoing, backlinks, blockBacklinks)
+	templ.Handler(component).ServeHTTP(w, r)
 }
 
 func (c *Controller) HandleEditZettelForm(w http.ResponseWriter, r *http.Request) {
-	workspaceId, err := uuid.Parse(r.PathValue("id"))
+	workspaceId, err := pathID(r, "id")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 
-	zetID, err := uuid.Parse(r.PathValue("zettelId"))
+	zetID, err := pathID(r, "zettelId")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 
-	zet, err := c.zettelRepo.FindByID(zetID)
+	zet, err := c.zettelRepo.FindByID(r.Context(), zetID)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 	component := view.EditZettelForm(workspaceId, zet)
@@ -226,37 +439,105 @@ func (c *Controller) HandleEditZettelForm(w http.ResponseWriter, r *http.Request
 }
 
 func (c *Controller) HandleEditZettel(w http.ResponseWriter, r *http.Request) {
-	zetID, err := uuid.Parse(r.PathValue("zettelId"))
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	zetID, err := pathID(r, "zettelId")
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
 
-	zet, err := c.zettelRepo.FindByID(zetID)
+	zet, err := c.zettelRepo.FindByID(r.Context(), zetID)
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
-	zet.SetTitle(r.FormValue("title"))
 	zet.SetBody(r.FormValue("content"))
 	zet.SetKind(zettel.Kind(r.FormValue("kind")))
+	if err := zet.ReplaceAliases(strings.Split(r.FormValue("aliases"), ",")); err != nil {
+		renderError(w, r, err)
+		return
+	}
 
-	if err := c.zettelRepo.Save(zet); err != nil {
-		c.renderError(w, r, err)
+	// a new title renames the zettel, as the rename form does
+	if title := r.FormValue("title"); title != zet.Title() {
+		var plan service.Rename
+		plan, err = c.service.PlanRename(r.Context(), zetID, title)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+		err = c.service.SaveRenamedZettel(r.Context(), zet, workspaceID, plan)
+	} else {
+		err = c.service.SaveZettel(r.Context(), zet, workspaceID)
+	}
+	if err != nil {
+		renderError(w, r, err)
 		return
 	}
 	c.HandleListZettels(w, r)
 }
 
 func (c *Controller) HandleDeleteZettel(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("zettelId")
-	zettID, err := uuid.Parse(id)
+	zettID, err := pathID(r, "zettelId")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if err := c.zettelRepo.Delete(r.Context(), zettID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+	c.HandleListZettels(w, r)
+}
+
+func (c *Controller) HandleRenameZettelForm(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := pathID(r, "id")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	zetID, err := pathID(r, "zettelId")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	zet, err := c.zettelRepo.FindByID(r.Context(), zetID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	// Planning a rename to the current title previews the affected zettels
+	plan, err := c.service.PlanRename(r.Context(), zetID, zet.Title())
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	component := view.RenameZettelForm(workspaceID, plan)
+	templ.Handler(component).ServeHTTP(w, r)
+}
+
+func (c *Controller) HandleRenameZettel(w http.ResponseWriter, r *http.Request) {
+	zetID, err := pathID(r, "zettelId")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	plan, err := c.service.PlanRename(r.Context(), zetID, r.FormValue("title"))
 	if err != nil {
-		c.renderError(w, r, err)
+		renderError(w, r, err)
 		return
 	}
-	if err := c.zettelRepo.Delete(zettID); err != nil {
-		c.renderError(w, r, err)
+	if err := c.service.Rename(r.Context(), plan); err != nil {
+		renderError(w, r, err)
 		return
 	}
 	c.HandleListZettels(w, r)
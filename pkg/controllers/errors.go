@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/httperror"
+	"github.com/odas0r/zet/pkg/router/middleware"
+	"github.com/odas0r/zet/pkg/view"
+)
+
+var errPageNotFound = shared.NotFound("error: page not found")
+
+// renderError answers with the status of the error. An htmx request gets
+// the message swapped into the errors element, leaving the page as it was,
+// and any other request a page with the message.
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status := httperror.Status(err)
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	var component templ.Component
+	if r.Header.Get("HX-Request") == "true" {
+		httperror.Retarget(w)
+		component = view.ErrorAlert(httperror.Message(err))
+	} else {
+		component = view.ErrorPage(status, httperror.Message(err))
+	}
+	templ.Handler(component, templ.WithStatus(status)).ServeHTTP(w, r)
+}
+
+// NotFound answers the requests for pages that do not exist.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	renderError(w, r, errPageNotFound)
+}
+
+// MethodNotAllowed answers the requests for pages that exist, but not with
+// the method of the request.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	renderError(w, r, httperror.ErrMethodNotAllowed)
+}
+
+// Deny answers the requests a middleware turns away before WithLayout, e.g.
+// without the CSRF token of the session, so the page is put in the layout
+// here.
+func Deny(w http.ResponseWriter, r *http.Request, err error) {
+	middleware.WithLayout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renderError(w, r, err)
+	})).ServeHTTP(w, r)
+}
+
+// pathID parses the id in the path, which names no page when malformed.
+func pathID(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		return uuid.Nil, errPageNotFound
+	}
+	return id, nil
+}
@@ -13,6 +13,7 @@ import (
 type Database struct {
 	DB                    *sqlx.DB
 	url                   string
+	path                  string
 	maxOpenConnections    int
 	maxIdleConnections    int
 	connectionMaxLifetime time.Duration
@@ -31,8 +32,8 @@ type Options struct {
 
 func init() {
 	driver := &sqlite3.SQLiteDriver{}
-	sql.Register("sqlite3_extended", driver)
-	sql.Register("sqlite3_extended_with_logs", sqlhooks.Wrap(driver, QueryLogger()))
+	sql.Register("sqlite3_extended", sqlhooks.Wrap(driver, &queryHook{}))
+	sql.Register("sqlite3_extended_with_logs", sqlhooks.Wrap(driver, &queryHook{logQueries: true}))
 }
 
 // New with the given options.
@@ -42,10 +43,12 @@ func New(opts Options) *Database {
 	// - Set WAL mode (not strictly necessary each time because it's persisted in the database, but good for first run)
 	// - Set busy timeout, so concurrent writers wait on each other instead of erroring immediately
 	// - Enable foreign key checks
+	path := opts.URL
 	opts.URL += "?_journal=WAL&_timeout=5000&_fk=true"
 
 	return &Database{
 		url:                   opts.URL,
+		path:                  path,
 		maxOpenConnections:    opts.MaxOpenConnections,
 		maxIdleConnections:    opts.MaxIdleConnections,
 		connectionMaxLifetime: opts.ConnectionMaxLifetime,
@@ -54,7 +57,13 @@ func New(opts Options) *Database {
 	}
 }
 
+// Connect opens the connection pool, shared by every repository of the
+// database, so it is only opened the first time.
 func (d *Database) Connect() error {
+	if d.DB != nil {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -79,6 +88,14 @@ func (d *Database) Connect() error {
 	return nil
 }
 
+// Close closes the connection pool, after the queries in flight finish.
+func (d *Database) Close() error {
+	if d.DB == nil {
+		return nil
+	}
+	return d.DB.Close()
+}
+
 func (d *Database) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Transaction, error) {
 	tx, err := d.DB.BeginTxx(ctx, opts)
 	if err != nil {
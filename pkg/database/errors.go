@@ -0,0 +1,14 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// IsConstraint reports whether err is the failure of a constraint, or of a
+// trigger raising an abort, with the given message.
+func IsConstraint(err error, message string) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint && sqliteErr.Error() == message
+}
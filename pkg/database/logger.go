@@ -2,48 +2,48 @@ package database
 
 import (
 	"context"
-	"log"
-	"os"
+	"log/slog"
 	"time"
+
+	"github.com/odas0r/zet/pkg/logger"
 )
 
 var started int
 
-const (
-	reset  = "\033[0m"
-	red    = "\033[31m"
-	green  = "\033[32m"
-	yellow = "\033[33m"
-	blue   = "\033[34m"
-)
-
-type logger interface {
-	Printf(string, ...interface{})
+// queryHook measures the queries, adds up those run for a request and, with
+// logQueries, logs each of them at the debug level and the failed ones at
+// the warn level.
+type queryHook struct {
+	logQueries bool
 }
 
-type hookLogger struct {
-	log logger
+func (h *queryHook) Before(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
+	return context.WithValue(ctx, &started, time.Now()), nil
 }
 
-func QueryLogger() *hookLogger {
-	return &hookLogger{
-		log: log.New(os.Stderr, "", log.LstdFlags),
+func (h *queryHook) After(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
+	duration := h.done(ctx, "ok")
+	if h.logQueries {
+		slog.DebugContext(ctx, "query", "query", query, "args", args, "duration", duration)
 	}
+	return ctx, nil
 }
 
-func (h *hookLogger) Before(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
-	return context.WithValue(ctx, &started, time.Now()), nil
-}
-
-func (h *hookLogger) After(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
-	duration := time.Since(ctx.Value(&started).(time.Time))
-	h.log.Printf("%s%s Args: `%q`. Took: %s%s", blue, query, args, duration, reset)
-	return ctx, nil
+func (h *queryHook) OnError(ctx context.Context, err error, query string, args ...interface{}) error {
+	duration := h.done(ctx, "error")
+	if h.logQueries {
+		// the caller decides whether the error is one, e.g. a constraint
+		// violation can be a conflict to report
+		slog.WarnContext(ctx, "query failed", "error", err, "query", query, "args", args, "duration", duration)
+	}
+	return err
 }
 
-func (h *hookLogger) OnError(ctx context.Context, err error, query string, args ...interface{}) error {
+func (h *queryHook) done(ctx context.Context, result string) time.Duration {
 	duration := time.Since(ctx.Value(&started).(time.Time))
-	h.log.Printf("%sError: %v, Query: `%s`, Args: `%q`, Took: %s%s",
-		red, err, query, args, duration, reset)
-	return err
+	QueryDuration.Observe(duration.Seconds(), result)
+	if request, ok := logger.FromContext(ctx); ok {
+		request.AddQuery(duration)
+	}
+	return duration
 }
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/odas0r/zet/pkg/metrics"
+)
+
+// QueryDuration measures the queries of every database, by whether they
+// succeeded ("ok") or failed ("error").
+var QueryDuration = metrics.NewHistogram("zet_db_query_duration_seconds",
+	"Time to run a query, by result.",
+	[]float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+	"result",
+)
+
+// Count returns the number of rows of the table.
+func (d *Database) Count(ctx context.Context, table string) (int, error) {
+	var count int
+	err := d.DB.GetContext(ctx, &count, fmt.Sprintf("select count(*) from %q", table))
+	return count, err
+}
+
+// Size returns the size of the database files, in bytes. In WAL mode the
+// changes since the last checkpoint are in the -wal file, with its index in
+// the -shm file, so they count too while they exist.
+func (d *Database) Size() (int64, error) {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	for _, suffix := range []string{"-wal", "-shm"} {
+		info, err := os.Stat(d.path + suffix)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
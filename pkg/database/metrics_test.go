@@ -0,0 +1,40 @@
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/database"
+)
+
+func TestDatabase_Size(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zettel.db")
+	// the log is removed with the last connection, so one is kept open
+	db := database.New(database.Options{URL: path, MaxOpenConnections: 1, MaxIdleConnections: 1})
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer db.DB.Close()
+
+	// the table is written to the write-ahead log until a checkpoint
+	if _, err := db.DB.Exec(`create table note (body text); insert into note values (zeroblob(65536))`); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected int64
+	for _, name := range []string{path, path + "-wal", path + "-shm"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected += info.Size()
+	}
+	size, err := db.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != expected {
+		t.Errorf("expected %d bytes, got %d", expected, size)
+	}
+}
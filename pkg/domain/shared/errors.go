@@ -0,0 +1,32 @@
+package shared
+
+import "errors"
+
+// The kinds of the errors of the domain, to tell apart with errors.Is.
+var (
+	// ErrNotFound is the kind of the errors about something that does not
+	// exist.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalid is the kind of the errors about values that break a rule of
+	// the domain.
+	ErrInvalid = errors.New("invalid")
+	// ErrConflict is the kind of the errors about something that clashes
+	// with what already exists.
+	ErrConflict = errors.New("conflict")
+)
+
+// Error is an error of the domain, of one of the kinds above.
+type Error struct {
+	kind    error
+	message string
+}
+
+func (e *Error) Error() string { return e.message }
+func (e *Error) Unwrap() error { return e.kind }
+
+// Kind returns ErrNotFound, ErrInvalid or ErrConflict.
+func (e *Error) Kind() error { return e.kind }
+
+func NotFound(message string) error { return &Error{kind: ErrNotFound, message: message} }
+func Invalid(message string) error  { return &Error{kind: ErrInvalid, message: message} }
+func Conflict(message string) error { return &Error{kind: ErrConflict, message: message} }
@@ -0,0 +1,8 @@
+package shared
+
+// Page selects a slice of a list: the Limit items that follow the first
+// Offset ones.
+type Page struct {
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,21 @@
+package token
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+)
+
+var (
+	ErrTokenNotFound = shared.NotFound("error: token not found")
+)
+
+type Repository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (Token, error)
+	FindByName(ctx context.Context, name string) (Token, error)
+	FindByHash(ctx context.Context, hash string) (Token, error)
+	FindAll(ctx context.Context) ([]Token, error)
+	Save(ctx context.Context, t Token) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,33 @@
+package sqlite_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/token/sqlite"
+)
+
+var (
+	repo *sqlite.SQLiteRepository
+	ctx  = context.Background()
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	repo, err = sqlite.New(
+		database.New(database.Options{
+			URL:                "../../../../zettel.db",
+			MaxOpenConnections: 1,
+			MaxIdleConnections: 1,
+			LogQueries:         true,
+		}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to set up test repository: %v", err)
+	}
+
+	// Run the tests
+	m.Run()
+}
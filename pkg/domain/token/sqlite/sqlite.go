@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/shared/sqlite"
+	"github.com/odas0r/zet/pkg/domain/token"
+)
+
+type SQLiteRepository struct {
+	db *sqlx.DB
+}
+
+type sqliteToken struct {
+	ID        uuid.UUID    `db:"id"`
+	Name      string       `db:"name"`
+	Hash      string       `db:"hash"`
+	Scope     string       `db:"scope"`
+	CreatedAt *sqlite.Time `db:"created_at"`
+	UpdatedAt *sqlite.Time `db:"updated_at"`
+}
+
+func NewFromToken(t token.Token) sqliteToken {
+	return sqliteToken{
+		ID:        t.ID(),
+		Name:      t.Name(),
+		Hash:      t.Hash(),
+		Scope:     string(t.Scope()),
+		CreatedAt: &sqlite.Time{T: t.Timestamp().Created},
+		UpdatedAt: &sqlite.Time{T: t.Timestamp().Updated},
+	}
+}
+
+func (st sqliteToken) ToAggregate() token.Token {
+	t := token.Token{}
+	t.SetID(st.ID)
+	t.SetName(st.Name)
+	t.SetHash(st.Hash)
+	t.SetScope(token.Scope(st.Scope))
+	t.SetCreated(st.CreatedAt.T)
+	t.SetUpdated(st.UpdatedAt.T)
+	return t
+}
+
+func New(database *database.Database) (*SQLiteRepository, error) {
+	if err := database.Connect(); err != nil {
+		return nil, err
+	}
+
+	return &SQLiteRepository{
+		db: database.DB,
+	}, nil
+}
+
+func (r *SQLiteRepository) FindByID(ctx context.Context, id uuid.UUID) (token.Token, error) {
+	return r.findOne(ctx, `where id = $1`, id)
+}
+
+func (r *SQLiteRepository) FindByName(ctx context.Context, name string) (token.Token, error) {
+	return r.findOne(ctx, `where name = $1`, name)
+}
+
+func (r *SQLiteRepository) FindByHash(ctx context.Context, hash string) (token.Token, error) {
+	return r.findOne(ctx, `where hash = $1`, hash)
+}
+
+func (r *SQLiteRepository) findOne(ctx context.Context, where string, arg any) (token.Token, error) {
+	var st sqliteToken
+
+	query := `
+  select id, name, hash, scope, created_at, updated_at
+  from token
+  ` + where
+
+	if err := r.db.GetContext(ctx, &st, query, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return token.Token{}, token.ErrTokenNotFound
+		}
+		return token.Token{}, err
+	}
+	return st.ToAggregate(), nil
+}
+
+func (r *SQLiteRepository) FindAll(ctx context.Context) ([]token.Token, error) {
+	query := `
+  select id, name, hash, scope, created_at, updated_at
+  from token
+  order by created_at
+  `
+
+	var results []sqliteToken
+	if err := r.db.SelectContext(ctx, &results, query); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]token.Token, len(results))
+	for i, row := range results {
+		tokens[i] = row.ToAggregate()
+	}
+	return tokens, nil
+}
+
+func (r *SQLiteRepository) Save(ctx context.Context, t token.Token) error {
+	query := `
+  insert into token (id, name, hash, scope, created_at, updated_at)
+  values (:id, :name, :hash, :scope, :created_at, :updated_at)
+  on conflict (id) do
+  update set name = excluded.name, scope = excluded.scope, updated_at = excluded.updated_at
+  `
+
+	_, err := r.db.NamedExecContext(ctx, query, NewFromToken(t))
+	return err
+}
+
+func (r *SQLiteRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `delete from token where id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,63 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/token"
+)
+
+func TestSQLite_Token(t *testing.T) {
+	tok, secret, err := token.New("test-"+uuid.NewString(), token.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, tok); err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		test        string
+		find        func() (token.Token, error)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			test: "Found by id",
+			find: func() (token.Token, error) { return repo.FindByID(ctx, tok.ID()) },
+		},
+		{
+			test: "Found by name",
+			find: func() (token.Token, error) { return repo.FindByName(ctx, tok.Name()) },
+		},
+		{
+			test: "Found by the hash of its secret",
+			find: func() (token.Token, error) { return repo.FindByHash(ctx, token.Hash(secret)) },
+		},
+		{
+			test:        "No token with the secret",
+			find:        func() (token.Token, error) { return repo.FindByHash(ctx, token.Hash(secret+"x")) },
+			expectedErr: token.ErrTokenNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			found, err := tc.find()
+			if err != tc.expectedErr {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if err == nil && (found.ID() != tok.ID() || found.Scope() != token.Read) {
+				t.Errorf("expected token %s with scope read, got %s with scope %s", tok.ID(), found.ID(), found.Scope())
+			}
+		})
+	}
+
+	if err := repo.Delete(ctx, tok.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(ctx, tok.ID()); err != token.ErrTokenNotFound {
+		t.Errorf("expected the revoked token to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/domain/shared/timestamp"
+)
+
+// Prefix starts every token secret, so that it is easy to spot.
+const Prefix = "zet_"
+
+var (
+	ErrMissingName       = shared.Invalid("error: the token needs a name")
+	ErrInvalidScope      = shared.Invalid("error: the scope must be read or write")
+	ErrNameAlreadyExists = shared.Conflict("error: a token with that name already exists")
+)
+
+// Scope is what a token is allowed to do.
+type Scope string
+
+const (
+	// Read only lets through the requests that change nothing.
+	Read Scope = "read"
+	// Write lets through every request.
+	Write Scope = "write"
+)
+
+func ParseScope(s string) (Scope, error) {
+	switch Scope(s) {
+	case Read, Write:
+		return Scope(s), nil
+	}
+	return "", ErrInvalidScope
+}
+
+// Allows tells whether the scope lets through a request with the method.
+func (s Scope) Allows(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return s == Read || s == Write
+	}
+	return s == Write
+}
+
+// Token grants access to the web server. Only the hash of its secret is
+// kept, so the secret is shown once, when the token is created.
+type Token struct {
+	id        uuid.UUID
+	name      string
+	hash      string
+	scope     Scope
+	timestamp timestamp.Timestamp
+}
+
+// New creates a token along with its secret.
+func New(name string, scope Scope) (Token, string, error) {
+	if name == "" {
+		return Token{}, "", ErrMissingName
+	}
+	if _, err := ParseScope(string(scope)); err != nil {
+		return Token{}, "", err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return Token{}, "", err
+	}
+	secret := Prefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return Token{
+		id:        uuid.New(),
+		name:      name,
+		hash:      Hash(secret),
+		scope:     scope,
+		timestamp: timestamp.New(),
+	}, secret, nil
+}
+
+// Hash returns the hash a token secret is stored and looked up by. Secrets
+// are random, so a fast hash is enough.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Getters
+func (t *Token) ID() uuid.UUID                  { return t.id }
+func (t *Token) Name() string                   { return t.name }
+func (t *Token) Hash() string                   { return t.hash }
+func (t *Token) Scope() Scope                   { return t.scope }
+func (t *Token) Timestamp() timestamp.Timestamp { return t.timestamp }
+
+// Setters
+func (t *Token) SetID(id uuid.UUID)           { t.id = id }
+func (t *Token) SetName(name string)          { t.name = name }
+func (t *Token) SetHash(hash string)          { t.hash = hash }
+func (t *Token) SetScope(scope Scope)         { t.scope = scope }
+func (t *Token) SetCreated(created time.Time) { t.timestamp.Created = created }
+func (t *Token) SetUpdated(updated time.Time) { t.timestamp.Updated = updated }
@@ -0,0 +1,55 @@
+package token_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/domain/token"
+)
+
+func TestToken_New(t *testing.T) {
+	tok, secret, err := token.New("laptop", token.Write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, token.Prefix) {
+		t.Errorf("expected the secret to start with %q, got %q", token.Prefix, secret)
+	}
+	if tok.Hash() == secret || tok.Hash() != token.Hash(secret) {
+		t.Errorf("expected the token to keep the hash of its secret")
+	}
+
+	if _, _, err := token.New("", token.Write); err != token.ErrMissingName {
+		t.Errorf("expected %v, got %v", token.ErrMissingName, err)
+	}
+	if _, _, err := token.New("laptop", "admin"); err != token.ErrInvalidScope {
+		t.Errorf("expected %v, got %v", token.ErrInvalidScope, err)
+	}
+}
+
+func TestScope_Allows(t *testing.T) {
+	type testCase struct {
+		test     string
+		scope    token.Scope
+		method   string
+		expected bool
+	}
+
+	testCases := []testCase{
+		{test: "read gets", scope: token.Read, method: http.MethodGet, expected: true},
+		{test: "read does not post", scope: token.Read, method: http.MethodPost, expected: false},
+		{test: "read does not delete", scope: token.Read, method: http.MethodDelete, expected: false},
+		{test: "write gets", scope: token.Write, method: http.MethodGet, expected: true},
+		{test: "write patches", scope: token.Write, method: http.MethodPatch, expected: true},
+		{test: "unknown scope", scope: "admin", method: http.MethodGet, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			if got := tc.scope.Allows(tc.method); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
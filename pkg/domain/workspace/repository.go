@@ -1,19 +1,24 @@
 package workspace
 
 import (
-	"errors"
+	"context"
 
 	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
 )
 
 var (
-	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrWorkspaceNotFound = shared.NotFound("workspace not found")
 )
 
 type Repository interface {
-	FindWorkspaceByID(id uuid.UUID) (Workspace, error)
-	FindAllWorkspaces() ([]Workspace, error)
-	Save(workspace Workspace) error
-	Update(w Workspace) error
-	Delete(id uuid.UUID) error
+	FindWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
+	FindAllWorkspaces(ctx context.Context) ([]Workspace, error)
+	// FindWorkspacePage returns a page of the workspaces, along with their
+	// number.
+	FindWorkspacePage(ctx context.Context, page shared.Page) ([]Workspace, int, error)
+	FindWorkspacesByZettelID(ctx context.Context, id uuid.UUID) ([]Workspace, error)
+	Save(ctx context.Context, workspace Workspace) error
+	Update(ctx context.Context, w Workspace) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -1,13 +1,16 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/shared"
 	"github.com/odas0r/zet/pkg/domain/shared/sqlite"
 	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/domain/zettel"
 )
 
 type SQLiteRepository struct {
@@ -59,7 +62,7 @@ func New(database *database.Database) (*SQLiteRepository, error) {
 	}, nil
 }
 
-func (r *SQLiteRepository) FindWorkspaceByID(id uuid.UUID) (workspace.Workspace, error) {
+func (r *SQLiteRepository) FindWorkspaceByID(ctx context.Context, id uuid.UUID) (workspace.Workspace, error) {
 	var sw sqliteWorkspace
 
 	query := `
@@ -68,7 +71,7 @@ func (r *SQLiteRepository) FindWorkspaceByID(id uuid.UUID) (workspace.Workspace,
   where id = $1
   `
 
-	if err := r.db.Get(&sw, query, id); err != nil {
+	if err := r.db.GetContext(ctx, &sw, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return workspace.Workspace{}, workspace.ErrWorkspaceNotFound
 		}
@@ -82,27 +85,27 @@ func (r *SQLiteRepository) FindWorkspaceByID(id uuid.UUID) (workspace.Workspace,
   where workspace_id = $1
   `
 	var zettelIDs []uuid.UUID
-	if err := r.db.Select(&zettelIDs, zettelsQuery, id); err != nil {
+	if err := r.db.SelectContext(ctx, &zettelIDs, zettelsQuery, id); err != nil {
 		return workspace.Workspace{}, err
 	}
 
 	return sw.ToAggregate(zettelIDs), nil
 }
 
-func (r *SQLiteRepository) FindAllWorkspaces() ([]workspace.Workspace, error) {
+func (r *SQLiteRepository) FindAllWorkspaces(ctx context.Context) ([]workspace.Workspace, error) {
 	query := `
   SELECT id, path, created_at, updated_at
   FROM workspace
   `
 
 	var results []sqliteWorkspace
-	if err := r.db.Select(&results, query); err != nil {
+	if err := r.db.SelectContext(ctx, &results, query); err != nil {
 		return nil, err
 	}
 
 	workspaces := make([]workspace.Workspace, len(results))
 	for i, row := range results {
-		zettelIDs, err := r.findZettelIDsByWorkspaceID(row.ID)
+		zettelIDs, err := r.findZettelIDsByWorkspaceID(ctx, row.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -112,23 +115,80 @@ func (r *SQLiteRepository) FindAllWorkspaces() ([]workspace.Workspace, error) {
 	return workspaces, nil
 }
 
-func (r *SQLiteRepository) findZettelIDsByWorkspaceID(workspaceID uuid.UUID) ([]uuid.UUID, error) {
+// FindWorkspacePage returns a page of the workspaces, oldest first, and
+// the number of workspaces.
+func (r *SQLiteRepository) FindWorkspacePage(ctx context.Context, page shared.Page) ([]workspace.Workspace, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `select count(*) from workspace`); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+  select id, path, created_at, updated_at
+  from workspace
+  order by created_at, id
+  limit $1 offset $2
+  `
+
+	var results []sqliteWorkspace
+	if err := r.db.SelectContext(ctx, &results, query, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+
+	workspaces := make([]workspace.Workspace, len(results))
+	for i, row := range results {
+		zettelIDs, err := r.findZettelIDsByWorkspaceID(ctx, row.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		workspaces[i] = row.ToAggregate(zettelIDs)
+	}
+
+	return workspaces, total, nil
+}
+
+func (r *SQLiteRepository) FindWorkspacesByZettelID(ctx context.Context, zettelID uuid.UUID) ([]workspace.Workspace, error) {
+	query := `
+  select w.id, w.path, w.created_at, w.updated_at
+  from workspace w
+  join workspace_zettel wz on wz.workspace_id = w.id
+  where wz.zettel_id = $1
+  `
+
+	var results []sqliteWorkspace
+	if err := r.db.SelectContext(ctx, &results, query, zettelID); err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]workspace.Workspace, len(results))
+	for i, row := range results {
+		zettelIDs, err := r.findZettelIDsByWorkspaceID(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		workspaces[i] = row.ToAggregate(zettelIDs)
+	}
+
+	return workspaces, nil
+}
+
+func (r *SQLiteRepository) findZettelIDsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]uuid.UUID, error) {
 	query := `
   select zettel_id
   from workspace_zettel
   where workspace_id = $1
   `
 	var zettelIDs []uuid.UUID
-	if err := r.db.Select(&zettelIDs, query, workspaceID); err != nil {
+	if err := r.db.SelectContext(ctx, &zettelIDs, query, workspaceID); err != nil {
 		return nil, err
 	}
 	return zettelIDs, nil
 }
 
-func (r *SQLiteRepository) Save(w workspace.Workspace) error {
+func (r *SQLiteRepository) Save(ctx context.Context, w workspace.Workspace) error {
 	internal := NewFromWorkspace(w)
 
-	tx, err := r.db.Beginx()
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -140,14 +200,14 @@ func (r *SQLiteRepository) Save(w workspace.Workspace) error {
 	update set path = excluded.path, updated_at = excluded.updated_at
   `
 
-	_, err = tx.NamedExec(query, internal)
+	_, err = tx.NamedExecContext(ctx, query, internal)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	// Save workspace zettels
-	err = r.saveWorkspaceZettels(tx, internal.ID, w.ListZettelIDs())
+	err = r.saveWorkspaceZettels(ctx, tx, internal.ID, w.ListZettelIDs())
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -156,10 +216,10 @@ func (r *SQLiteRepository) Save(w workspace.Workspace) error {
 	return tx.Commit()
 }
 
-func (r *SQLiteRepository) Update(w workspace.Workspace) error {
+func (r *SQLiteRepository) Update(ctx context.Context, w workspace.Workspace) error {
 	internal := NewFromWorkspace(w)
 
-	tx, err := r.db.Beginx()
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -170,14 +230,14 @@ func (r *SQLiteRepository) Update(w workspace.Workspace) error {
   where id = :id
   `
 
-	_, err = tx.NamedExec(query, internal)
+	_, err = tx.NamedExecContext(ctx, query, internal)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	// Save workspace zettels
-	err = r.saveWorkspaceZettels(tx, internal.ID, w.ListZettelIDs())
+	err = r.saveWorkspaceZettels(ctx, tx, internal.ID, w.ListZettelIDs())
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -186,16 +246,16 @@ func (r *SQLiteRepository) Update(w workspace.Workspace) error {
 	return tx.Commit()
 }
 
-func (r *SQLiteRepository) Delete(id uuid.UUID) error {
+func (r *SQLiteRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `delete from workspace where id = $1`
-	_, err := r.db.Exec(query, id)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
-func (r *SQLiteRepository) saveWorkspaceZettels(tx *sqlx.Tx, workspaceID uuid.UUID, zettelIDs []uuid.UUID) error {
+func (r *SQLiteRepository) saveWorkspaceZettels(ctx context.Context, tx *sqlx.Tx, workspaceID uuid.UUID, zettelIDs []uuid.UUID) error {
 	// Delete existing workspace zettels
 	delQuery := `delete from workspace_zettel where workspace_id = $1`
-	_, err := tx.Exec(delQuery, workspaceID)
+	_, err := tx.ExecContext(ctx, delQuery, workspaceID)
 	if err != nil {
 		return err
 	}
@@ -206,8 +266,16 @@ func (r *SQLiteRepository) saveWorkspaceZettels(tx *sqlx.Tx, workspaceID uuid.UU
   values ($1, $2)
   `
 	for _, zID := range zettelIDs {
-		_, err = tx.Exec(insQuery, workspaceID, zID)
+		_, err = tx.ExecContext(ctx, insQuery, workspaceID, zID)
 		if err != nil {
+			// an alias of the zettel names another zettel of the workspace
+			if database.IsConstraint(err, zettel.ErrAliasAlreadyExists.Error()) {
+				return zettel.ErrAliasAlreadyExists
+			}
+			// or its sequence identifier is taken in the workspace
+			if database.IsConstraint(err, zettel.ErrSequenceAlreadyExists.Error()) {
+				return zettel.ErrSequenceAlreadyExists
+			}
 			return err
 		}
 	}
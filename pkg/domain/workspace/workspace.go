@@ -1,18 +1,18 @@
 package workspace
 
 import (
-	"errors"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
 	"github.com/odas0r/zet/pkg/domain/shared/timestamp"
 )
 
 var (
-	ErrInvalidPath         = errors.New("invalid workspace path")
-	ErrZettelNotFound      = errors.New("zettel not found in workspace")
-	ErrZettelAlreadyExists = errors.New("zettel already exists in workspace")
+	ErrInvalidPath         = shared.Invalid("invalid workspace path")
+	ErrZettelNotFound      = shared.NotFound("zettel not found in workspace")
+	ErrZettelAlreadyExists = shared.Conflict("zettel already exists in workspace")
 )
 
 type Workspace struct {
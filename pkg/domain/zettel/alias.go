@@ -0,0 +1,57 @@
+package zettel
+
+import (
+	"strings"
+
+	"github.com/odas0r/zet/pkg/domain/shared"
+)
+
+var (
+	ErrAliasAlreadyExists = shared.Conflict("alias already exists")
+)
+
+// AddAlias registers another name under which the zettel can be referenced.
+// Aliases are compared without case, so [[zk]] finds the zettel aliased ZK.
+func (z *Zettel) AddAlias(name string) error {
+	if name == "" {
+		return ErrMissingValues
+	} else if name == z.Title() {
+		return ErrAliasAlreadyExists
+	}
+	for _, alias := range z.aliases {
+		if strings.EqualFold(alias, name) {
+			return ErrAliasAlreadyExists
+		}
+	}
+	z.aliases = append(z.aliases, name)
+	return nil
+}
+
+// ReplaceAliases sets the aliases of the zettel to the given names and the
+// ones declared in its front matter.
+func (z *Zettel) ReplaceAliases(names []string) error {
+	z.aliases = []string{}
+	for _, name := range append(names, ParseFrontMatter(z.Content()).Aliases...) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := z.AddAlias(name); err != nil && err != ErrAliasAlreadyExists {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasName reports whether the zettel is known by the given title or alias.
+func (z *Zettel) HasName(name string) bool {
+	if z.Title() == name {
+		return true
+	}
+	for _, alias := range z.aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package zettel
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/odas0r/zet/pkg/domain/shared"
+)
+
+var (
+	ErrBlockNotFound = shared.NotFound("error: block not found")
+)
+
+var (
+	// BlockRegex matches the ^block-id anchor at the end of a line, the id
+	// being its first group. The markdown renderer finds the anchors with it
+	// too, so that the blocks rendered are the blocks parsed.
+	BlockRegex    = regexp.MustCompile(`\s\^([A-Za-z0-9-]+)\s*$`)
+	listItemRegex = regexp.MustCompile(`^\s*([-*+]|[0-9]+[.)])\s`)
+)
+
+// Block is a paragraph or list item ending with a ^block-id anchor, so it can
+// be linked to with [[Title#^block-id]]. The id is written in the content
+// itself, so it stays the same however the rest of the zettel is edited.
+type Block struct {
+	ID      string
+	Content string
+}
+
+// ParseBlocks returns the blocks of the body in order of appearance,
+// ignoring anchors inside fenced code blocks. When an id is used twice, the
+// first block keeps it.
+func ParseBlocks(body string) []Block {
+	lines := strings.Split(body, "\n")
+
+	var blocks []Block
+	seen := map[string]bool{}
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if isFence(trimmed) {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		m := BlockRegex.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		id := line[m[2]:m[3]]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// the block starts at the list item or paragraph the line ends
+		start := i
+		for start > 0 && !listItemRegex.MatchString(lines[start]) {
+			prev := lines[start-1]
+			if strings.TrimSpace(prev) == "" || isFence(strings.TrimSpace(prev)) || headingRegex.MatchString(prev) {
+				break
+			}
+			start--
+		}
+
+		content := append([]string{}, lines[start:i]...)
+		content = append(content, line[:m[0]])
+		blocks = append(blocks, Block{
+			ID:      id,
+			Content: strings.TrimSpace(strings.Join(content, "\n")),
+		})
+	}
+	return blocks
+}
+
+// Blocks returns the blocks of the zettel content.
+func (z *Zettel) Blocks() []Block {
+	return ParseBlocks(z.Content())
+}
+
+// Block returns the block of the zettel with the given id.
+func (z *Zettel) Block(id string) (Block, bool) {
+	for _, b := range z.Blocks() {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Block{}, false
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+}
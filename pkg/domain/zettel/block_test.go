@@ -0,0 +1,53 @@
+package zettel_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestZettel_ParseBlocks(t *testing.T) {
+	type testCase struct {
+		test     string
+		content  string
+		expected []zettel.Block
+	}
+
+	testCases := []testCase{
+		{
+			test:     "should take the whole paragraph",
+			content:  "# Heading\nfirst line\nsecond line ^quote\n\nafter",
+			expected: []zettel.Block{{ID: "quote", Content: "first line\nsecond line"}},
+		},
+		{
+			test:     "should take a single list item",
+			content:  "- one\n- two ^two\n- three",
+			expected: []zettel.Block{{ID: "two", Content: "- two"}},
+		},
+		{
+			test:     "should ignore anchors in code blocks",
+			content:  "```\ncode ^code\n```",
+			expected: nil,
+		},
+		{
+			test:     "should keep the first block of a repeated id",
+			content:  "first ^id\n\nsecond ^id",
+			expected: []zettel.Block{{ID: "id", Content: "first"}},
+		},
+		{
+			test:     "should ignore carets inside a line",
+			content:  "2^10 is 1024",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			blocks := zettel.ParseBlocks(tc.content)
+			if !reflect.DeepEqual(blocks, tc.expected) {
+				t.Errorf("expected blocks %v, got %v", tc.expected, blocks)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package zettel
+
+import "github.com/google/uuid"
+
+// EventType tells what happened to a zettel.
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event tells that a zettel was created, updated or deleted.
+type Event struct {
+	Type EventType `json:"type"`
+	ID   uuid.UUID `json:"id"`
+}
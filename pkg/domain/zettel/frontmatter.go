@@ -0,0 +1,94 @@
+package zettel
+
+import "strings"
+
+// FrontMatter is the metadata in the header delimited by "---" lines at the
+// top of a zettel content, e.g.
+//
+//	---
+//	aliases: [ZK, Slip box]
+//	tags: [method]
+//	---
+type FrontMatter struct {
+	Aliases []string
+	Tags    []string
+}
+
+// ParseFrontMatter reads the front matter of the body. Lists can be written
+// inline, as "key: [a, b]", or as "- item" lines below the key.
+func ParseFrontMatter(body string) FrontMatter {
+	var fm FrontMatter
+
+	lines := strings.Split(body, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return fm
+	}
+
+	key := ""
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" {
+			return fm
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && key != "" {
+			fm.set(key, []string{unquote(item)})
+			continue
+		}
+
+		k, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(k)
+
+		value = strings.TrimSpace(value)
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = unquote(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		fm.set(key, values)
+	}
+
+	// no closing delimiter, so it was not front matter
+	return FrontMatter{}
+}
+
+// StripFrontMatter returns the body without its front matter.
+func StripFrontMatter(body string) string {
+	lines := strings.Split(body, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return body
+	}
+	for i, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			return strings.Join(lines[i+2:], "\n")
+		}
+	}
+	return body
+}
+
+func (fm *FrontMatter) set(key string, values []string) {
+	switch key {
+	case "aliases":
+		fm.Aliases = append(fm.Aliases, values...)
+	case "tags":
+		fm.Tags = append(fm.Tags, values...)
+	}
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Tags returns the tags declared in the front matter of the zettel.
+func (z *Zettel) Tags() []string {
+	return ParseFrontMatter(z.Content()).Tags
+}
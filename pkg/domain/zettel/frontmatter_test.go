@@ -0,0 +1,59 @@
+package zettel_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestZettel_ParseFrontMatter(t *testing.T) {
+	type testCase struct {
+		test            string
+		content         string
+		expectedAliases []string
+	}
+
+	testCases := []testCase{
+		{
+			test:            "should parse inline lists",
+			content:         "---\naliases: [ZK, \"Slip box\"]\n---\nbody",
+			expectedAliases: []string{"ZK", "Slip box"},
+		},
+		{
+			test:            "should parse block lists",
+			content:         "---\ntitle: x\naliases:\n  - ZK\n  - 'Slip box'\ntags: [method]\n---\nbody",
+			expectedAliases: []string{"ZK", "Slip box"},
+		},
+		{
+			test:            "should ignore an unterminated header",
+			content:         "---\naliases: [ZK]\nbody",
+			expectedAliases: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			fm := zettel.ParseFrontMatter(tc.content)
+			if !reflect.DeepEqual(fm.Aliases, tc.expectedAliases) {
+				t.Errorf("expected aliases %v, got %v", tc.expectedAliases, fm.Aliases)
+			}
+		})
+	}
+}
+
+func TestZettel_ReplaceAliases(t *testing.T) {
+	z, err := zettel.New("Zettelkasten", "---\naliases: [ZK]\n---\nbody", zettel.Permanent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := z.ReplaceAliases([]string{" Slip box ", "", "ZK", "Zettelkasten"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"Slip box", "ZK"}
+	if !reflect.DeepEqual(z.Aliases(), expected) {
+		t.Errorf("expected aliases %v, got %v", expected, z.Aliases())
+	}
+}
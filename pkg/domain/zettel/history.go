@@ -0,0 +1,9 @@
+package zettel
+
+import "time"
+
+// Visit is an entry of the history of opened zettels.
+type Visit struct {
+	Zettel   Zettel
+	OpenedAt time.Time
+}
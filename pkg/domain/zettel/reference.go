@@ -0,0 +1,138 @@
+package zettel
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// referenceRegex matches wiki-links such as [[Title]], [[Title#Heading]] and
+// [[Title|label]].
+var referenceRegex = regexp.MustCompile(`\[\[([^\[\]|#]+)(#[^\[\]|]*)?(\|[^\[\]]*)?\]\]`)
+
+// Reference is a wiki-link to another zettel found in the body of a zettel.
+type Reference struct {
+	Title  string
+	Anchor string
+	Label  string
+}
+
+// BlockID returns the id of the block the reference points to, as in
+// [[Title#^block-id]], or an empty string when it does not point to a block.
+func (r Reference) BlockID() string {
+	if id, ok := strings.CutPrefix(r.Anchor, "^"); ok {
+		return id
+	}
+	return ""
+}
+
+// String returns the reference as a wiki-link.
+func (r Reference) String() string {
+	s := "[[" + r.Title
+	if r.Anchor != "" {
+		s += "#" + r.Anchor
+	}
+	if r.Label != "" {
+		s += "|" + r.Label
+	}
+	return s + "]]"
+}
+
+// ParseReferences returns every wiki-link found in the given body, in order
+// of appearance.
+func ParseReferences(body string) []Reference {
+	var refs []Reference
+	for _, m := range referenceRegex.FindAllStringSubmatch(body, -1) {
+		refs = append(refs, Reference{
+			Title:  strings.TrimSpace(m[1]),
+			Anchor: strings.TrimPrefix(m[2], "#"),
+			Label:  strings.TrimPrefix(m[3], "|"),
+		})
+	}
+	return refs
+}
+
+// References returns the wiki-links found in the zettel content.
+func (z *Zettel) References() []Reference {
+	return ParseReferences(z.Content())
+}
+
+// ReferencesTitle reports whether the zettel content has a wiki-link to the given
+// title.
+func (z *Zettel) ReferencesTitle(title string) bool {
+	for _, ref := range z.References() {
+		if ref.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceReferences rewrites every wiki-link to oldTitle so it points to
+// newTitle, keeping anchors and labels intact. It reports whether the content
+// was changed.
+func (z *Zettel) ReplaceReferences(oldTitle, newTitle string) bool {
+	changed := false
+	body := referenceRegex.ReplaceAllStringFunc(z.Content(), func(link string) string {
+		m := referenceRegex.FindStringSubmatch(link)
+		if strings.TrimSpace(m[1]) != oldTitle {
+			return link
+		}
+		changed = true
+		return "[[" + newTitle + m[2] + m[3] + "]]"
+	})
+	if changed {
+		z.SetBody(body)
+	}
+	return changed
+}
+
+// ResolveLinks replaces the outgoing links of the zettel with links to the
+// zettels referenced in its content. resolve returns the id of the zettel
+// known by the given title or alias; references it cannot resolve are
+// skipped.
+func (z *Zettel) ResolveLinks(resolve func(name string) (uuid.UUID, bool)) {
+	existing := map[uuid.UUID]Link{}
+	for _, link := range z.links {
+		existing[link.To] = link
+	}
+
+	links := []Link{}
+	seen := map[uuid.UUID]bool{}
+	for _, ref := range z.References() {
+		to, ok := resolve(ref.Title)
+		if !ok || to == z.id || seen[to] {
+			continue
+		}
+		seen[to] = true
+
+		if link, ok := existing[to]; ok {
+			links = append(links, link)
+		} else {
+			links = append(links, NewLink(z.id, to))
+		}
+	}
+	z.links = links
+}
+
+// FileName returns the name of the markdown file backing a zettel with the
+// given title, e.g. "My Title" becomes "my-title.md".
+func FileName(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-") + ".md"
+}
+
+// FileName returns the name of the markdown file backing the zettel.
+func (z *Zettel) FileName() string { return FileName(z.Title()) }
@@ -0,0 +1,117 @@
+package zettel_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestZettel_ReplaceReferences(t *testing.T) {
+	type testCase struct {
+		test            string
+		content         string
+		expectedContent string
+		expectedChanged bool
+	}
+
+	testCases := []testCase{
+		{
+			test:            "should rewrite a plain reference",
+			content:         "see [[Old Title]] for more",
+			expectedContent: "see [[New Title]] for more",
+			expectedChanged: true,
+		},
+		{
+			test:            "should keep anchors and labels",
+			content:         "[[Old Title#Heading]] and [[Old Title|the old one]]",
+			expectedContent: "[[New Title#Heading]] and [[New Title|the old one]]",
+			expectedChanged: true,
+		},
+		{
+			test:            "should not rewrite references to other titles",
+			content:         "[[Old Title 2]] and [[Another]]",
+			expectedContent: "[[Old Title 2]] and [[Another]]",
+			expectedChanged: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			z, err := zettel.New("title", tc.content, zettel.Fleet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changed := z.ReplaceReferences("Old Title", "New Title")
+			if changed != tc.expectedChanged {
+				t.Errorf("expected changed %v, got %v", tc.expectedChanged, changed)
+			}
+			if z.Content() != tc.expectedContent {
+				t.Errorf("expected content %q, got %q", tc.expectedContent, z.Content())
+			}
+		})
+	}
+}
+
+func TestZettel_FileName(t *testing.T) {
+	if got := zettel.FileName("My Title: A Note!"); got != "my-title-a-note.md" {
+		t.Errorf("expected file name %q, got %q", "my-title-a-note.md", got)
+	}
+}
+
+func TestZettel_ResolveLinks(t *testing.T) {
+	target, _ := zettel.New("Target", "content", zettel.Fleet)
+	z, _ := zettel.New("title", "[[ZK]], [[Target#Heading]] and [[Missing]]", zettel.Fleet)
+
+	z.ResolveLinks(func(name string) (uuid.UUID, bool) {
+		if name == "ZK" || name == "Target" {
+			return target.ID(), true
+		}
+		return uuid.Nil, false
+	})
+
+	if len(z.Links()) != 1 || z.Links()[0].To != target.ID() {
+		t.Errorf("expected a single link to %s, got %v", target.ID(), z.Links())
+	}
+}
+
+func TestZettel_ResolveLinksRemovesLinks(t *testing.T) {
+	target, _ := zettel.New("Target", "content", zettel.Fleet)
+	removed, _ := zettel.New("Removed", "content", zettel.Fleet)
+	z, _ := zettel.New("title", "[[Target]] and [[Removed]]", zettel.Fleet)
+	resolve := func(name string) (uuid.UUID, bool) {
+		switch name {
+		case "Target":
+			return target.ID(), true
+		case "Removed":
+			return removed.ID(), true
+		}
+		return uuid.Nil, false
+	}
+
+	z.ResolveLinks(resolve)
+	kept := z.Links()[0]
+
+	z.SetBody("[[Target]]")
+	z.ResolveLinks(resolve)
+
+	if len(z.Links()) != 1 || z.Links()[0].To != target.ID() {
+		t.Fatalf("expected a link to %s only, got %v", target.ID(), z.Links())
+	}
+	if z.Links()[0] != kept {
+		t.Errorf("expected the link to keep its timestamp, got %v", z.Links()[0])
+	}
+}
+
+func TestZettel_ResolveLinksOfEmbeds(t *testing.T) {
+	target, _ := zettel.New("Target", "content", zettel.Fleet)
+	z, _ := zettel.New("title", "![[Target#Heading]]", zettel.Fleet)
+
+	z.ResolveLinks(func(name string) (uuid.UUID, bool) {
+		return target.ID(), name == "Target"
+	})
+
+	if len(z.Links()) != 1 || z.Links()[0].To != target.ID() {
+		t.Errorf("expected the embed to link to %s, got %v", target.ID(), z.Links())
+	}
+}
@@ -1,20 +1,50 @@
 package zettel
 
 import (
-	"errors"
+	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
 )
 
 var (
 	// ErrZettelNotFound is returned when a zettel is not found.
-	ErrZettelNotFound = errors.New("error: zettel not found")
+	ErrZettelNotFound = shared.NotFound("error: zettel not found")
+	// ErrTitleAlreadyExists is returned when another zettel already has the
+	// given title.
+	ErrTitleAlreadyExists = shared.Conflict("error: a zettel with this title already exists")
 )
 
 type Repository interface {
-	FindByID(id uuid.UUID) (Zettel, error)
-	FindZettelsByWorkspaceID(id uuid.UUID) ([]Zettel, error)
-	Save(zettel Zettel) error
-	Update(z Zettel) error
-	Delete(id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (Zettel, error)
+	FindAll(ctx context.Context) ([]Zettel, error)
+	FindByTitle(ctx context.Context, title string) (Zettel, error)
+	FindByNameInWorkspace(ctx context.Context, workspaceID uuid.UUID, name string) (Zettel, error)
+	FindByAliasInWorkspace(ctx context.Context, workspaceID uuid.UUID, alias string) (Zettel, error)
+	FindBySequenceInWorkspace(ctx context.Context, workspaceID uuid.UUID, seq Sequence) (Zettel, error)
+	SearchByName(ctx context.Context, query string) ([]Zettel, error)
+	FindZettelsByWorkspaceID(ctx context.Context, id uuid.UUID) ([]Zettel, error)
+	// FindPage, FindPageInWorkspace and SearchPageByName return a page of
+	// the list, along with its length.
+	FindPage(ctx context.Context, page shared.Page) ([]Zettel, int, error)
+	FindPageInWorkspace(ctx context.Context, workspaceID uuid.UUID, page shared.Page) ([]Zettel, int, error)
+	SearchPageByName(ctx context.Context, query string, page shared.Page) ([]Zettel, int, error)
+	FindReferencing(ctx context.Context, title string) ([]Zettel, error)
+	FindLinking(ctx context.Context, id uuid.UUID) ([]Zettel, error)
+	FindBlock(ctx context.Context, zettelID uuid.UUID, blockID string) (Block, error)
+	FindHistory(ctx context.Context, page shared.Page) ([]Visit, int, error)
+	// FindVersions returns the time each zettel was last updated, to find
+	// the zettels that changed since.
+	FindVersions(ctx context.Context) (map[uuid.UUID]time.Time, error)
+	// FindVersion returns the last time a zettel was updated and the number
+	// of zettels, which change along with any zettel.
+	FindVersion(ctx context.Context) (time.Time, int, error)
+	AddToHistory(ctx context.Context, id uuid.UUID) error
+	Save(ctx context.Context, zettel Zettel) error
+	SaveAll(ctx context.Context, zettels []Zettel) error
+	Split(ctx context.Context, z Zettel, parts []Zettel) error
+	Merge(ctx context.Context, keep Zettel, absorbID uuid.UUID) error
+	Update(ctx context.Context, z Zettel) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
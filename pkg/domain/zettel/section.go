@@ -0,0 +1,177 @@
+package zettel
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+)
+
+var (
+	ErrHeadingNotFound = shared.NotFound("heading not found")
+)
+
+var headingRegex = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// Section is a part of the zettel content that starts at a markdown heading
+// and ends at the next heading of the same or a higher level.
+type Section struct {
+	Heading string
+	Level   int
+	// Start and End are the line numbers of the heading and the line after
+	// the section.
+	Start int
+	End   int
+}
+
+// ParseSections returns the sections of the body in order of appearance,
+// ignoring headings inside fenced code blocks.
+func ParseSections(body string) []Section {
+	lines := strings.Split(body, "\n")
+
+	var sections []Section
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if isFence(trimmed) {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		m := headingRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		level := len(m[1])
+		// close the open sections this heading ends
+		for j := range sections {
+			if sections[j].End == 0 && sections[j].Level >= level {
+				sections[j].End = i
+			}
+		}
+		sections = append(sections, Section{Heading: m[2], Level: level, Start: i})
+	}
+	for j := range sections {
+		if sections[j].End == 0 {
+			sections[j].End = len(lines)
+		}
+	}
+	return sections
+}
+
+// Sections returns the sections of the zettel content.
+func (z *Zettel) Sections() []Section {
+	return ParseSections(z.Content())
+}
+
+// Split moves the sections under the given headings into new zettels of the
+// same kind, titled after the heading, and replaces them with a link to the
+// new zettel. The outgoing links whose reference moved along with a section
+// are moved to the new zettel; linkNames maps the target of each link to the
+// titles and aliases it can be referenced by.
+func (z *Zettel) Split(headings []string, linkNames map[uuid.UUID][]string) ([]Zettel, error) {
+	sections := z.Sections()
+	lines := strings.Split(z.Content(), "\n")
+
+	var chosen []Section
+	taken := map[int]bool{}
+	for _, heading := range headings {
+		found := false
+		// a heading given again is the next section under it
+		for _, s := range sections {
+			if s.Heading == heading && !taken[s.Start] {
+				chosen = append(chosen, s)
+				taken[s.Start] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrHeadingNotFound
+		}
+	}
+
+	sort.Slice(chosen, func(i, j int) bool { return chosen[i].Start < chosen[j].Start })
+
+	var parts []Zettel
+	var content []string
+	next := 0
+	for _, s := range chosen {
+		if s.Start < next {
+			// nested in a section that was already split off
+			continue
+		}
+		// trailing blank lines stay, separating the link from what follows
+		end := s.End
+		for end > s.Start+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+
+		body := strings.TrimSpace(strings.Join(lines[s.Start+1:end], "\n"))
+		part, err := New(s.Heading, body, z.kind)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+
+		content = append(content, lines[next:s.Start]...)
+		content = append(content, "[["+s.Heading+"]]")
+		next = end
+	}
+	content = append(content, lines[next:]...)
+	z.SetBody(strings.Join(content, "\n"))
+
+	for i := range parts {
+		part := &parts[i]
+		for _, link := range z.links {
+			if !referencesAny(part, linkNames[link.To]) {
+				continue
+			}
+			if err := part.Link(link.To); err != nil && err != ErrLinkAlreadyExists {
+				return nil, err
+			}
+		}
+		if err := z.Link(part.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	// links that are no longer referenced by the remaining content moved to
+	// the new zettels
+	var links []Link
+	for _, link := range z.links {
+		if names, ok := linkNames[link.To]; ok && !referencesAny(z, names) && movedTo(parts, link.To) {
+			continue
+		}
+		links = append(links, link)
+	}
+	z.links = links
+	z.timestamp = z.timestamp.Update()
+
+	return parts, nil
+}
+
+func referencesAny(z *Zettel, names []string) bool {
+	for _, name := range names {
+		if z.ReferencesTitle(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func movedTo(parts []Zettel, to uuid.UUID) bool {
+	for _, part := range parts {
+		for _, link := range part.links {
+			if link.To == to {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package zettel_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestZettel_Split(t *testing.T) {
+	body := "intro [[A]]\n\n## First\n\nabout [[B]]\n\n### Nested\n\nmore\n\n## Second\n\n```\n## not a heading\n```\n"
+
+	a, _ := zettel.New("A", "a", zettel.Fleet)
+	b, _ := zettel.New("B", "b", zettel.Fleet)
+	z, _ := zettel.New("long note", body, zettel.Permanent)
+	z.Link(a.ID())
+	z.Link(b.ID())
+
+	sections := z.Sections()
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(sections))
+	}
+
+	parts, err := z.Split([]string{"Nested", "First"}, map[uuid.UUID][]string{
+		a.ID(): {"A"},
+		b.ID(): {"B"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parts) != 1 {
+		t.Fatalf("expected nested sections to move with their parent, got %d parts", len(parts))
+	}
+	part := parts[0]
+	if part.Title() != "First" || part.Kind() != zettel.Permanent {
+		t.Errorf("unexpected part %q of kind %q", part.Title(), part.Kind())
+	}
+	if part.Content() != "about [[B]]\n\n### Nested\n\nmore" {
+		t.Errorf("unexpected part content %q", part.Content())
+	}
+	if z.Content() != "intro [[A]]\n\n[[First]]\n\n## Second\n\n```\n## not a heading\n```\n" {
+		t.Errorf("unexpected remaining content %q", z.Content())
+	}
+	if len(part.Links()) != 1 || part.Links()[0].To != b.ID() {
+		t.Errorf("expected the link to B to move to the part, got %v", part.Links())
+	}
+
+	var to []uuid.UUID
+	for _, link := range z.Links() {
+		to = append(to, link.To)
+	}
+	if len(to) != 2 || to[0] != a.ID() || to[1] != part.ID() {
+		t.Errorf("expected links to A and the part, got %v", to)
+	}
+
+	if _, err := z.Split([]string{"Missing"}, nil); err != zettel.ErrHeadingNotFound {
+		t.Errorf("expected error %v, got %v", zettel.ErrHeadingNotFound, err)
+	}
+}
+
+func TestZettel_SplitRepeatedHeading(t *testing.T) {
+	z, _ := zettel.New("long note", "## Notes\n\nfirst\n\n## Other\n\n## Notes\n\nsecond\n", zettel.Fleet)
+
+	// the heading given twice is the sections under it in turn
+	parts, err := z.Split([]string{"Notes", "Notes"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || parts[0].Content() != "first" || parts[1].Content() != "second" {
+		t.Fatalf("expected both sections to be split off, got %v", parts)
+	}
+
+	if _, err := z.Split([]string{"Other", "Other"}, nil); err != zettel.ErrHeadingNotFound {
+		t.Errorf("expected error %v, got %v", zettel.ErrHeadingNotFound, err)
+	}
+}
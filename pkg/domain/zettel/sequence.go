@@ -0,0 +1,172 @@
+package zettel
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/odas0r/zet/pkg/domain/shared"
+)
+
+var (
+	ErrInvalidSequence       = shared.Invalid("invalid sequence identifier")
+	ErrMissingSequence       = shared.Invalid("zettel has no sequence identifier")
+	ErrSequenceAlreadyExists = shared.Conflict("sequence identifier already exists in workspace")
+)
+
+var (
+	sequenceRegex = regexp.MustCompile(`^[1-9][0-9]*([a-z]+[1-9][0-9]*)*[a-z]*$`)
+	segmentRegex  = regexp.MustCompile(`[0-9]+|[a-z]+`)
+)
+
+// Sequence is a Folgezettel identifier in the style of Luhmann, e.g. 1, 1a,
+// 1a1. It alternates numbers and letters, each new segment branching off the
+// note identified by the previous ones.
+type Sequence string
+
+func ParseSequence(s string) (Sequence, error) {
+	if !sequenceRegex.MatchString(s) {
+		return "", ErrInvalidSequence
+	}
+	return Sequence(s), nil
+}
+
+// RootSequence is the sequence of the n-th top-level note.
+func RootSequence(n int) Sequence {
+	return Sequence(strconv.Itoa(n))
+}
+
+func (s Sequence) segments() []string {
+	return segmentRegex.FindAllString(string(s), -1)
+}
+
+// Parent returns the sequence this one branches off, or an empty sequence
+// for top-level notes.
+func (s Sequence) Parent() Sequence {
+	segments := s.segments()
+	if len(segments) <= 1 {
+		return ""
+	}
+	last := segments[len(segments)-1]
+	return s[:len(s)-len(last)]
+}
+
+// Next returns the sequence of the note that continues this one, e.g. 1a is
+// followed by 1b and 1a1 by 1a2.
+func (s Sequence) Next() Sequence {
+	segments := s.segments()
+	last := segments[len(segments)-1]
+
+	var next string
+	if n, err := strconv.Atoi(last); err == nil {
+		next = strconv.Itoa(n + 1)
+	} else {
+		next = nextLetters(last)
+	}
+	return s[:len(s)-len(last)] + Sequence(next)
+}
+
+// Branch returns the sequence of the first note branching off this one, e.g.
+// 1 branches into 1a and 1a into 1a1.
+func (s Sequence) Branch() Sequence {
+	segments := s.segments()
+	if _, err := strconv.Atoi(segments[len(segments)-1]); err == nil {
+		return s + "a"
+	}
+	return s + "1"
+}
+
+// Less orders sequences the way the notes are filed: 1, 1a, 1a1, 1b, 2, 10.
+func (s Sequence) Less(other Sequence) bool {
+	a, b := s.segments(), other.segments()
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		// numbers and letters alternate in the same way for every sequence,
+		// so both segments are of the same type
+		if len(a[i]) != len(b[i]) {
+			return len(a[i]) < len(b[i])
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
+
+// nextLetters increments a letter segment: a, b, ..., z, aa, ab.
+func nextLetters(s string) string {
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 'z' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = 'a'
+	}
+	return "a" + string(b)
+}
+
+// SequenceNode is a zettel in the tree formed by the sequence identifiers.
+type SequenceNode struct {
+	Zettel   Zettel
+	Children []*SequenceNode
+}
+
+// SequenceTree arranges the zettels with a sequence identifier as a tree.
+// Notes whose parent is missing are shown at the top level.
+func SequenceTree(zettels []Zettel) []*SequenceNode {
+	var sequenced []Zettel
+	for _, z := range zettels {
+		if z.Sequence() != "" {
+			sequenced = append(sequenced, z)
+		}
+	}
+	sort.Slice(sequenced, func(i, j int) bool {
+		return sequenced[i].Sequence().Less(sequenced[j].Sequence())
+	})
+
+	nodes := map[Sequence]*SequenceNode{}
+	var roots []*SequenceNode
+	for _, z := range sequenced {
+		node := &SequenceNode{Zettel: z}
+		nodes[z.Sequence()] = node
+
+		// parents sort before their children, so they are already placed
+		parent, ok := nodes[z.Sequence().Parent()]
+		if ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Siblings returns the zettels that share the parent of the sequence,
+// including the zettel with the sequence itself, in filing order.
+func Siblings(zettels []Zettel, seq Sequence) []Zettel {
+	var siblings []Zettel
+	for _, z := range zettels {
+		if z.Sequence() != "" && z.Sequence().Parent() == seq.Parent() {
+			siblings = append(siblings, z)
+		}
+	}
+	sort.Slice(siblings, func(i, j int) bool {
+		return siblings[i].Sequence().Less(siblings[j].Sequence())
+	})
+	return siblings
+}
+
+// Children returns the zettels that branch off the sequence, in filing order.
+func Children(zettels []Zettel, seq Sequence) []Zettel {
+	var children []Zettel
+	for _, z := range zettels {
+		if z.Sequence() != "" && z.Sequence().Parent() == seq {
+			children = append(children, z)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Sequence().Less(children[j].Sequence())
+	})
+	return children
+}
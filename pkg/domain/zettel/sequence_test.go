@@ -0,0 +1,88 @@
+package zettel_test
+
+import (
+	"testing"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestZettel_Sequence(t *testing.T) {
+	type testCase struct {
+		test           string
+		sequence       zettel.Sequence
+		expectedNext   zettel.Sequence
+		expectedBranch zettel.Sequence
+		expectedParent zettel.Sequence
+	}
+
+	testCases := []testCase{
+		{
+			test:           "top-level sequence",
+			sequence:       "9",
+			expectedNext:   "10",
+			expectedBranch: "9a",
+			expectedParent: "",
+		},
+		{
+			test:           "letter segment",
+			sequence:       "1z",
+			expectedNext:   "1aa",
+			expectedBranch: "1z1",
+			expectedParent: "1",
+		},
+		{
+			test:           "number segment",
+			sequence:       "1a12",
+			expectedNext:   "1a13",
+			expectedBranch: "1a12a",
+			expectedParent: "1a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			if got := tc.sequence.Next(); got != tc.expectedNext {
+				t.Errorf("expected next %q, got %q", tc.expectedNext, got)
+			}
+			if got := tc.sequence.Branch(); got != tc.expectedBranch {
+				t.Errorf("expected branch %q, got %q", tc.expectedBranch, got)
+			}
+			if got := tc.sequence.Parent(); got != tc.expectedParent {
+				t.Errorf("expected parent %q, got %q", tc.expectedParent, got)
+			}
+		})
+	}
+
+	if _, err := zettel.ParseSequence("1aA"); err != zettel.ErrInvalidSequence {
+		t.Errorf("expected error %v, got %v", zettel.ErrInvalidSequence, err)
+	}
+}
+
+func TestZettel_SequenceTree(t *testing.T) {
+	var zettels []zettel.Zettel
+	for _, seq := range []zettel.Sequence{"10", "1a1", "2", "1", "1a", "1b", "3a"} {
+		z, _ := zettel.New(string(seq), "content", zettel.Permanent)
+		z.SetSequence(seq)
+		zettels = append(zettels, z)
+	}
+
+	roots := zettel.SequenceTree(zettels)
+
+	var order []zettel.Sequence
+	for _, root := range roots {
+		order = append(order, root.Zettel.Sequence())
+	}
+	expected := []zettel.Sequence{"1", "2", "3a", "10"}
+	if len(order) != len(expected) {
+		t.Fatalf("expected roots %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected roots %v, got %v", expected, order)
+		}
+	}
+
+	if len(roots[0].Children) != 2 || len(roots[0].Children[0].Children) != 1 {
+		t.Errorf("expected 1 to branch into 1a and 1b, and 1a into 1a1")
+	}
+}
@@ -1,6 +1,7 @@
 package sqlite_test
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -8,7 +9,10 @@ import (
 	"github.com/odas0r/zet/pkg/domain/zettel/sqlite"
 )
 
-var repo *sqlite.SQLiteRepository
+var (
+	repo *sqlite.SQLiteRepository
+	ctx  = context.Background()
+)
 
 func TestMain(m *testing.M) {
 	var err error
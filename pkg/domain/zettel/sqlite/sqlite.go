@@ -1,11 +1,15 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/shared"
 	"github.com/odas0r/zet/pkg/domain/shared/sqlite"
 	"github.com/odas0r/zet/pkg/domain/shared/timestamp"
 	"github.com/odas0r/zet/pkg/domain/zettel"
@@ -16,14 +20,17 @@ type SQLiteRepository struct {
 }
 
 type sqliteZettel struct {
-	ID      uuid.UUID    `db:"id"`
-	Title   string       `db:"title"`
-	Content string       `db:"content"`
-	Kind    zettel.Kind  `db:"kind"`
-	Created *sqlite.Time `db:"created_at"`
-	Updated *sqlite.Time `db:"updated_at"`
-
-	Links []sqliteLink `db:"-"`
+	ID       uuid.UUID       `db:"id"`
+	Title    string          `db:"title"`
+	Content  string          `db:"content"`
+	Kind     zettel.Kind     `db:"kind"`
+	Sequence zettel.Sequence `db:"sequence"`
+	Created  *sqlite.Time    `db:"created_at"`
+	Updated  *sqlite.Time    `db:"updated_at"`
+
+	Links   []sqliteLink  `db:"-"`
+	Aliases []string      `db:"-"`
+	Blocks  []sqliteBlock `db:"-"`
 }
 
 type sqliteLink struct {
@@ -33,6 +40,12 @@ type sqliteLink struct {
 	UpdatedAt *sqlite.Time `db:"updated_at"`
 }
 
+type sqliteBlock struct {
+	ZettelID uuid.UUID `db:"zettel_id"`
+	ID       string    `db:"id"`
+	Content  string    `db:"content"`
+}
+
 // NewFromZettel takes in an aggregate root and returns a struct that can be
 // used to interact with the database
 func NewFromZettel(z zettel.Zettel) sqliteZettel {
@@ -46,14 +59,26 @@ func NewFromZettel(z zettel.Zettel) sqliteZettel {
 		})
 	}
 
+	var blocks []sqliteBlock
+	for _, block := range z.Blocks() {
+		blocks = append(blocks, sqliteBlock{
+			ZettelID: z.ID(),
+			ID:       block.ID,
+			Content:  block.Content,
+		})
+	}
+
 	return sqliteZettel{
-		ID:      z.ID(),
-		Title:   z.Title(),
-		Content: z.Content(),
-		Kind:    z.Kind(),
-		Created: &sqlite.Time{T: z.Timestamp().Created},
-		Updated: &sqlite.Time{T: z.Timestamp().Updated},
-		Links:   links,
+		ID:       z.ID(),
+		Title:    z.Title(),
+		Content:  z.Content(),
+		Kind:     z.Kind(),
+		Sequence: z.Sequence(),
+		Created:  &sqlite.Time{T: z.Timestamp().Created},
+		Updated:  &sqlite.Time{T: z.Timestamp().Updated},
+		Links:    links,
+		Aliases:  z.Aliases(),
+		Blocks:   blocks,
 	}
 }
 
@@ -65,6 +90,7 @@ func (sz sqliteZettel) ToAggregate() zettel.Zettel {
 	z.SetTitle(sz.Title)
 	z.SetBody(sz.Content)
 	z.SetKind(sz.Kind)
+	z.SetSequence(sz.Sequence)
 	z.SetCreated(sz.Created.T)
 	z.SetUpdated(sz.Updated.T)
 
@@ -80,6 +106,7 @@ func (sz sqliteZettel) ToAggregate() zettel.Zettel {
 		})
 	}
 	z.SetLinks(domainLinks)
+	z.SetAliases(sz.Aliases)
 
 	return z
 }
@@ -94,16 +121,16 @@ func New(database *database.Database) (*SQLiteRepository, error) {
 	}, nil
 }
 
-func (r *SQLiteRepository) FindByID(id uuid.UUID) (zettel.Zettel, error) {
+func (r *SQLiteRepository) FindByID(ctx context.Context, id uuid.UUID) (zettel.Zettel, error) {
 	var sz sqliteZettel
 
 	query := `
-  select id, title, content, kind, created_at, updated_at
+  select id, title, content, kind, sequence, created_at, updated_at
   from zettel
   where id = $1
   `
 
-	if err := r.db.Get(&sz, query, id); err != nil {
+	if err := r.db.GetContext(ctx, &sz, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return zettel.Zettel{}, zettel.ErrZettelNotFound
 		}
@@ -117,52 +144,502 @@ func (r *SQLiteRepository) FindByID(id uuid.UUID) (zettel.Zettel, error) {
   where zettel_id = $1
   `
 	var links []sqliteLink
-	if err := r.db.Select(&links, linksQuery, id); err != nil {
+	if err := r.db.SelectContext(ctx, &links, linksQuery, id); err != nil {
 		return zettel.Zettel{}, err
 	}
 
 	// Set links in the struct
 	sz.Links = links
 
+	aliasesQuery := `
+  select name
+  from alias
+  where zettel_id = $1
+  order by created_at, name
+  `
+	var aliases []string
+	if err := r.db.SelectContext(ctx, &aliases, aliasesQuery, id); err != nil {
+		return zettel.Zettel{}, err
+	}
+	sz.Aliases = aliases
+
 	return sz.ToAggregate(), nil
 }
 
-func (r *SQLiteRepository) Save(z zettel.Zettel) error {
-	internal := NewFromZettel(z)
+// FindByTitle returns the zettel with the given title, falling back to the
+// zettel that has it as an alias, whatever its case.
+func (r *SQLiteRepository) FindByTitle(ctx context.Context, title string) (zettel.Zettel, error) {
+	query := `
+  select id from (
+    select id, 0 as rank, created_at from zettel where title = $1
+    union all
+    select zettel_id, 1 as rank, created_at from alias where lower(name) = lower($1)
+  )
+  order by rank, created_at
+  limit 1
+  `
+
+	var id uuid.UUID
+	if err := r.db.GetContext(ctx, &id, query, title); err != nil {
+		if err == sql.ErrNoRows {
+			return zettel.Zettel{}, zettel.ErrZettelNotFound
+		}
+		return zettel.Zettel{}, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// FindByNameInWorkspace returns the zettel of the workspace with the given
+// title or alias, the alias in any case.
+func (r *SQLiteRepository) FindByNameInWorkspace(ctx context.Context, workspaceID uuid.UUID, name string) (zettel.Zettel, error) {
+	query := `
+  select id from (
+    select z.id, 0 as rank, z.created_at
+    from zettel z
+    join workspace_zettel wz on wz.zettel_id = z.id
+    where wz.workspace_id = $1 and z.title = $2
+    union all
+    select a.zettel_id, 1 as rank, a.created_at
+    from alias a
+    join workspace_zettel wz on wz.zettel_id = a.zettel_id
+    where wz.workspace_id = $1 and lower(a.name) = lower($2)
+  )
+  order by rank, created_at
+  limit 1
+  `
+
+	var id uuid.UUID
+	if err := r.db.GetContext(ctx, &id, query, workspaceID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return zettel.Zettel{}, zettel.ErrZettelNotFound
+		}
+		return zettel.Zettel{}, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// FindByAliasInWorkspace returns the zettel of the workspace with the given
+// alias, in any case.
+func (r *SQLiteRepository) FindByAliasInWorkspace(ctx context.Context, workspaceID uuid.UUID, alias string) (zettel.Zettel, error) {
+	query := `
+  select a.zettel_id
+  from alias a
+  join workspace_zettel wz on wz.zettel_id = a.zettel_id
+  where wz.workspace_id = $1 and lower(a.name) = lower($2)
+  order by a.created_at
+  limit 1
+  `
+
+	var id uuid.UUID
+	if err := r.db.GetContext(ctx, &id, query, workspaceID, alias); err != nil {
+		if err == sql.ErrNoRows {
+			return zettel.Zettel{}, zettel.ErrZettelNotFound
+		}
+		return zettel.Zettel{}, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// FindBySequenceInWorkspace returns the zettel of the workspace with the
+// given sequence identifier.
+func (r *SQLiteRepository) FindBySequenceInWorkspace(ctx context.Context, workspaceID uuid.UUID, seq zettel.Sequence) (zettel.Zettel, error) {
+	query := `
+  select z.id
+  from zettel z
+  join workspace_zettel wz on wz.zettel_id = z.id
+  where wz.workspace_id = $1 and z.sequence = $2
+  `
+
+	var id uuid.UUID
+	if err := r.db.GetContext(ctx, &id, query, workspaceID, seq); err != nil {
+		if err == sql.ErrNoRows {
+			return zettel.Zettel{}, zettel.ErrZettelNotFound
+		}
+		return zettel.Zettel{}, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// SearchByName returns the zettels whose title or one of its aliases
+// contains the query, ignoring case.
+func (r *SQLiteRepository) SearchByName(ctx context.Context, query string) ([]zettel.Zettel, error) {
+	searchQuery := `
+  select id from zettel where instr(lower(title), lower($1)) > 0
+  union
+  select zettel_id from alias where instr(lower(name), lower($1)) > 0
+  `
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, searchQuery, query); err != nil {
+		return nil, err
+	}
+
+	var zettels []zettel.Zettel
+	for _, id := range ids {
+		z, err := r.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		zettels = append(zettels, z)
+	}
+	return zettels, nil
+}
+
+// FindReferencing returns every zettel whose content has a wiki-link to the
+// given title.
+func (r *SQLiteRepository) FindReferencing(ctx context.Context, title string) ([]zettel.Zettel, error) {
+	query := `
+  select id
+  from zettel
+  where instr(content, $1) > 0
+  order by created_at
+  `
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, query, title); err != nil {
+		return nil, err
+	}
 
-	tx, err := r.db.Beginx()
+	var zettels []zettel.Zettel
+	for _, id := range ids {
+		z, err := r.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// the query is only a pre-filter, as the title may be spaced from
+		// the brackets, e.g. [[ Title ]], and [[Title 2]] also matches
+		if z.ReferencesTitle(title) {
+			zettels = append(zettels, z)
+		}
+	}
+	return zettels, nil
+}
+
+// FindLinking returns every zettel with a link to the zettel with the given
+// id.
+func (r *SQLiteRepository) FindLinking(ctx context.Context, id uuid.UUID) ([]zettel.Zettel, error) {
+	query := `
+  select z.id
+  from zettel z
+  join link l on l.zettel_id = z.id
+  where l.link_id = $1
+  order by z.created_at
+  `
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, query, id); err != nil {
+		return nil, err
+	}
+
+	var zettels []zettel.Zettel
+	for _, id := range ids {
+		z, err := r.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		zettels = append(zettels, z)
+	}
+	return zettels, nil
+}
+
+func (r *SQLiteRepository) Save(ctx context.Context, z zettel.Zettel) error {
+	return r.SaveAll(ctx, []zettel.Zettel{z})
+}
+
+// SaveAll inserts or updates the given zettels in a single transaction.
+func (r *SQLiteRepository) SaveAll(ctx context.Context, zettels []zettel.Zettel) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	for _, z := range zettels {
+		if err := r.save(ctx, tx, NewFromZettel(z)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteRepository) save(ctx context.Context, tx *sqlx.Tx, internal sqliteZettel) error {
 	query := `
-  insert into zettel (id, title, content, kind, updated_at, created_at)
-	values (:id, :title, :content, :kind, :updated_at, :created_at)
+  insert into zettel (id, title, content, kind, sequence, updated_at, created_at)
+	values (:id, :title, :content, :kind, :sequence, :updated_at, :created_at)
 	on conflict (id) do
-	update set title = excluded.title, content = excluded.content, kind = excluded.kind, updated_at = excluded.updated_at
+	update set title = excluded.title, content = excluded.content, kind = excluded.kind, sequence = excluded.sequence, updated_at = excluded.updated_at
   `
 
-	_, err = tx.NamedExec(query, internal)
+	_, err := tx.NamedExecContext(ctx, query, internal)
 	if err != nil {
-		tx.Rollback()
 		if err == sql.ErrNoRows {
 			return zettel.ErrZettelNotFound
 		}
+		if database.IsConstraint(err, zettel.ErrSequenceAlreadyExists.Error()) {
+			return fmt.Errorf("%w: %q", zettel.ErrSequenceAlreadyExists, internal.Sequence)
+		}
+		return err
+	}
+
+	if err := r.saveLinks(ctx, tx, internal.ID, internal.Links); err != nil {
+		return err
+	}
+
+	if err := r.saveAliases(ctx, tx, internal.ID, internal.Aliases); err != nil {
+		return err
+	}
+
+	return r.saveBlocks(ctx, tx, internal.ID, internal.Blocks)
+}
+
+func (r *SQLiteRepository) saveAliases(ctx context.Context, tx *sqlx.Tx, zettelID uuid.UUID, aliases []string) error {
+	delQuery := `delete from alias where zettel_id = $1`
+	if _, err := tx.ExecContext(ctx, delQuery, zettelID); err != nil {
+		return err
+	}
+
+	insQuery := `
+  insert into alias (zettel_id, name)
+  values ($1, $2)
+  `
+	for _, name := range aliases {
+		if _, err := tx.ExecContext(ctx, insQuery, zettelID, name); err != nil {
+			if database.IsConstraint(err, zettel.ErrAliasAlreadyExists.Error()) {
+				return fmt.Errorf("%w: %q", zettel.ErrAliasAlreadyExists, name)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// saveBlocks stores the blocks of the zettel, keeping the creation time of
+// the ones it already had.
+func (r *SQLiteRepository) saveBlocks(ctx context.Context, tx *sqlx.Tx, zettelID uuid.UUID, blocks []sqliteBlock) error {
+	var existing []string
+	if err := tx.SelectContext(ctx, &existing, `select id from block where zettel_id = $1`, zettelID); err != nil {
 		return err
 	}
 
-	err = r.saveLinks(tx, internal.ID, internal.Links)
+	kept := map[string]bool{}
+	for _, block := range blocks {
+		kept[block.ID] = true
+	}
+	for _, id := range existing {
+		if kept[id] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `delete from block where zettel_id = $1 and id = $2`, zettelID, id); err != nil {
+			return err
+		}
+	}
+
+	query := `
+  insert into block (zettel_id, id, content)
+  values ($1, $2, $3)
+  on conflict (zettel_id, id) do
+  update set content = excluded.content, updated_at = strftime('%Y-%m-%dT%H:%M:%fZ')
+  where content != excluded.content
+  `
+	for _, block := range blocks {
+		if _, err := tx.ExecContext(ctx, query, block.ZettelID, block.ID, block.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindBlock returns the block of the zettel with the given id.
+func (r *SQLiteRepository) FindBlock(ctx context.Context, zettelID uuid.UUID, blockID string) (zettel.Block, error) {
+	query := `
+  select zettel_id, id, content
+  from block
+  where zettel_id = $1 and id = $2
+  `
+	var block sqliteBlock
+	if err := r.db.GetContext(ctx, &block, query, zettelID, blockID); err != nil {
+		if err == sql.ErrNoRows {
+			return zettel.Block{}, zettel.ErrBlockNotFound
+		}
+		return zettel.Block{}, err
+	}
+	return zettel.Block{ID: block.ID, Content: block.Content}, nil
+}
+
+// FindHistory returns a page of the opened zettels, most recent first, and
+// the number of visits in the history.
+func (r *SQLiteRepository) FindHistory(ctx context.Context, page shared.Page) ([]zettel.Visit, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `select count(*) from history`); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+  select zettel_id, created_at
+  from history
+  order by created_at desc, rowid desc
+  limit $1 offset $2
+  `
+	var rows []struct {
+		ZettelID uuid.UUID    `db:"zettel_id"`
+		OpenedAt *sqlite.Time `db:"created_at"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ZettelID
+	}
+	zettels, err := r.findByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	visits := []zettel.Visit{}
+	for i, row := range rows {
+		visits = append(visits, zettel.Visit{Zettel: zettels[i], OpenedAt: row.OpenedAt.T})
+	}
+	return visits, total, nil
+}
+
+// FindVersions returns the updated_at of every zettel, without loading them.
+func (r *SQLiteRepository) FindVersions(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	query := `
+  select id, updated_at
+  from zettel
+  `
+	var rows []struct {
+		ID      uuid.UUID    `db:"id"`
+		Updated *sqlite.Time `db:"updated_at"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[uuid.UUID]time.Time, len(rows))
+	for _, row := range rows {
+		versions[row.ID] = row.Updated.T
+	}
+	return versions, nil
+}
+
+// FindVersion returns the last time a zettel was updated and the number of
+// zettels, without loading them.
+func (r *SQLiteRepository) FindVersion(ctx context.Context) (time.Time, int, error) {
+	query := `
+  select max(updated_at) as updated_at, count(*) as count
+  from zettel
+  `
+	var row struct {
+		Updated sqlite.Time `db:"updated_at"`
+		Count   int         `db:"count"`
+	}
+	if err := r.db.GetContext(ctx, &row, query); err != nil {
+		return time.Time{}, 0, err
+	}
+	return row.Updated.T, row.Count, nil
+}
+
+// AddToHistory records that the zettel was opened now. The entry is
+// replaced rather than updated, so its rowid orders visits made within the
+// same millisecond.
+func (r *SQLiteRepository) AddToHistory(ctx context.Context, id uuid.UUID) error {
+	query := `
+  insert or replace into history (zettel_id)
+  values ($1)
+  `
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Split saves the zettel and the parts split off from it in a single
+// transaction, adding the parts to every workspace the zettel belongs to.
+func (r *SQLiteRepository) Split(ctx context.Context, z zettel.Zettel, parts []zettel.Zettel) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
+		return err
+	}
+
+	// the parts are saved first, so the links to them can be inserted
+	for _, part := range append(parts, z) {
+		if err := r.save(ctx, tx, NewFromZettel(part)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	query := `
+  insert or ignore into workspace_zettel (workspace_id, zettel_id)
+  select workspace_id, $1 from workspace_zettel where zettel_id = $2
+  `
+	for _, part := range parts {
+		if _, err := tx.ExecContext(ctx, query, part.ID(), z.ID()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Merge saves keep, which has already absorbed the zettel with the given id,
+// re-points the links and workspace memberships of the absorbed zettel to
+// keep and deletes it, all in a single transaction.
+func (r *SQLiteRepository) Merge(ctx context.Context, keep zettel.Zettel, absorbID uuid.UUID) error {
+	internal := NewFromZettel(keep)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := r.save(ctx, tx, internal); err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	queries := []string{
+		// incoming links, skipping the ones keep already has
+		`insert or ignore into link (zettel_id, link_id, created_at, updated_at)
+     select zettel_id, $1, created_at, updated_at from link where link_id = $2 and zettel_id != $1`,
+		`insert or ignore into workspace_zettel (workspace_id, zettel_id)
+     select workspace_id, $1 from workspace_zettel where zettel_id = $2`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, keep.ID(), absorbID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// the remaining links and memberships of the absorbed zettel cascade
+	result, err := tx.ExecContext(ctx, `delete from zettel where id = $1`, absorbID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count == 0 {
+		tx.Rollback()
+		return zettel.ErrZettelNotFound
+	}
+
 	return tx.Commit()
 }
 
-func (r *SQLiteRepository) saveLinks(tx *sqlx.Tx, zettelID uuid.UUID, links []sqliteLink) error {
+func (r *SQLiteRepository) saveLinks(ctx context.Context, tx *sqlx.Tx, zettelID uuid.UUID, links []sqliteLink) error {
 	delQuery := `delete from link where zettel_id = $1`
-	_, err := tx.Exec(delQuery, zettelID)
+	_, err := tx.ExecContext(ctx, delQuery, zettelID)
 	if err != nil {
 		return err
 	}
@@ -172,7 +649,7 @@ func (r *SQLiteRepository) saveLinks(tx *sqlx.Tx, zettelID uuid.UUID, links []sq
   values (:zettel_id, :link_id, :created_at, :updated_at)
   `
 	for _, link := range links {
-		_, err = tx.NamedExec(insQuery, link)
+		_, err = tx.NamedExecContext(ctx, insQuery, link)
 		if err != nil {
 			return err
 		}
@@ -180,21 +657,43 @@ func (r *SQLiteRepository) saveLinks(tx *sqlx.Tx, zettelID uuid.UUID, links []sq
 	return nil
 }
 
-func (r *SQLiteRepository) FindZettelsByWorkspaceID(workspaceID uuid.UUID) ([]zettel.Zettel, error) {
+func (r *SQLiteRepository) FindAll(ctx context.Context) ([]zettel.Zettel, error) {
+	query := `
+  select id
+  from zettel
+  order by created_at
+  `
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, query); err != nil {
+		return nil, err
+	}
+
+	var zettels []zettel.Zettel
+	for _, id := range ids {
+		z, err := r.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		zettels = append(zettels, z)
+	}
+	return zettels, nil
+}
+
+func (r *SQLiteRepository) FindZettelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]zettel.Zettel, error) {
 	zettelsQuery := `
   select zettel_id
   from workspace_zettel
   where workspace_id = $1
   `
 	var zettelIDs []uuid.UUID
-	if err := r.db.Select(&zettelIDs, zettelsQuery, workspaceID); err != nil {
+	if err := r.db.SelectContext(ctx, &zettelIDs, zettelsQuery, workspaceID); err != nil {
 		return nil, err
 	}
 
 	// Fetch zettel details
 	var zettels []zettel.Zettel
 	for _, zID := range zettelIDs {
-		z, err := r.FindByID(zID)
+		z, err := r.FindByID(ctx, zID)
 		if err != nil {
 			return nil, err
 		}
@@ -203,17 +702,154 @@ func (r *SQLiteRepository) FindZettelsByWorkspaceID(workspaceID uuid.UUID) ([]ze
 	return zettels, nil
 }
 
-func (r *SQLiteRepository) Update(z zettel.Zettel) error {
+// FindPage returns a page of the zettels, oldest first, and the number of
+// zettels.
+func (r *SQLiteRepository) FindPage(ctx context.Context, page shared.Page) ([]zettel.Zettel, int, error) {
+	return r.findPage(ctx, page, `
+  select id
+  from zettel
+  order by created_at, id
+  limit $1 offset $2
+  `, `select count(*) from zettel`)
+}
+
+// FindPageInWorkspace returns a page of the zettels of the workspace, oldest
+// first, and the number of zettels of the workspace.
+func (r *SQLiteRepository) FindPageInWorkspace(ctx context.Context, workspaceID uuid.UUID, page shared.Page) ([]zettel.Zettel, int, error) {
+	return r.findPage(ctx, page, `
+  select z.id
+  from zettel z
+  join workspace_zettel wz on wz.zettel_id = z.id
+  where wz.workspace_id = $1
+  order by z.created_at, z.id
+  limit $2 offset $3
+  `, `select count(*) from workspace_zettel where workspace_id = $1`, workspaceID)
+}
+
+// SearchPageByName returns a page of the zettels whose title or one of its
+// aliases contains the query, ignoring case, and the number of them.
+func (r *SQLiteRepository) SearchPageByName(ctx context.Context, query string, page shared.Page) ([]zettel.Zettel, int, error) {
+	return r.findPage(ctx, page, `
+  select id
+  from zettel
+  where instr(lower(title), lower($1)) > 0
+    or id in (select zettel_id from alias where instr(lower(name), lower($1)) > 0)
+  order by created_at, id
+  limit $2 offset $3
+  `, `
+  select count(*)
+  from zettel
+  where instr(lower(title), lower($1)) > 0
+    or id in (select zettel_id from alias where instr(lower(name), lower($1)) > 0)
+  `, query)
+}
+
+// findPage returns the zettels of the page, along with the count of the
+// whole list. Both queries take args, and the query takes the limit and
+// offset of the page after them, as SQLite numbers the parameters in the
+// order they appear.
+func (r *SQLiteRepository) findPage(ctx context.Context, page shared.Page, query, countQuery string, args ...any) ([]zettel.Zettel, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, query, append(args, page.Limit, page.Offset)...); err != nil {
+		return nil, 0, err
+	}
+	zettels, err := r.findByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return zettels, total, nil
+}
+
+// findByIDs returns the zettels with the given ids, in the same order, with
+// a query for each of their parts rather than one for each zettel.
+func (r *SQLiteRepository) findByIDs(ctx context.Context, ids []uuid.UUID) ([]zettel.Zettel, error) {
+	zettels := []zettel.Zettel{}
+	if len(ids) == 0 {
+		return zettels, nil
+	}
+
+	query, args, err := sqlx.In(`
+  select id, title, content, kind, sequence, created_at, updated_at
+  from zettel
+  where id in (?)
+  `, ids)
+	if err != nil {
+		return nil, err
+	}
+	var rows []sqliteZettel
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	byID := map[uuid.UUID]*sqliteZettel{}
+	for i := range rows {
+		byID[rows[i].ID] = &rows[i]
+	}
+
+	query, args, err = sqlx.In(`
+  select zettel_id, link_id, created_at, updated_at
+  from link
+  where zettel_id in (?)
+  `, ids)
+	if err != nil {
+		return nil, err
+	}
+	var links []sqliteLink
+	if err := r.db.SelectContext(ctx, &links, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		byID[link.From].Links = append(byID[link.From].Links, link)
+	}
+
+	query, args, err = sqlx.In(`
+  select zettel_id, name
+  from alias
+  where zettel_id in (?)
+  order by created_at, name
+  `, ids)
+	if err != nil {
+		return nil, err
+	}
+	var aliases []struct {
+		ZettelID uuid.UUID `db:"zettel_id"`
+		Name     string    `db:"name"`
+	}
+	if err := r.db.SelectContext(ctx, &aliases, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		byID[alias.ZettelID].Aliases = append(byID[alias.ZettelID].Aliases, alias.Name)
+	}
+
+	for _, id := range ids {
+		sz, ok := byID[id]
+		if !ok {
+			return nil, zettel.ErrZettelNotFound
+		}
+		zettels = append(zettels, sz.ToAggregate())
+	}
+	return zettels, nil
+}
+
+func (r *SQLiteRepository) Update(ctx context.Context, z zettel.Zettel) error {
 	internal := NewFromZettel(z)
 
 	query := `
 	update zettel
-	set title = :title, content = :content, kind = :kind, updated_at = :updated_at
+	set title = :title, content = :content, kind = :kind, sequence = :sequence, updated_at = :updated_at
 	where id = :id
 	`
 
-	result, err := r.db.NamedExec(query, internal)
+	result, err := r.db.NamedExecContext(ctx, query, internal)
 	if err != nil {
+		if database.IsConstraint(err, zettel.ErrSequenceAlreadyExists.Error()) {
+			return fmt.Errorf("%w: %q", zettel.ErrSequenceAlreadyExists, internal.Sequence)
+		}
 		return err
 	}
 
@@ -229,13 +865,13 @@ func (r *SQLiteRepository) Update(z zettel.Zettel) error {
 	return nil
 }
 
-func (r *SQLiteRepository) Delete(id uuid.UUID) error {
+func (r *SQLiteRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `
   delete from zettel
   where id = $1
   `
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
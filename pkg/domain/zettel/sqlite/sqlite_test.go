@@ -1,9 +1,13 @@
 package sqlite_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
 	"github.com/odas0r/zet/pkg/domain/zettel"
 )
 
@@ -12,7 +16,7 @@ func TestSQLite_SaveZettel(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if err := repo.Save(z); err != nil {
+	if err := repo.Save(ctx, z); err != nil {
 		t.Error(err)
 	}
 }
@@ -29,7 +33,7 @@ func TestSQLite_GetZettel(t *testing.T) {
 		t.Error(err)
 	}
 
-	if err := repo.Save(z); err != nil {
+	if err := repo.Save(ctx, z); err != nil {
 		t.Error(err)
 	}
 
@@ -48,7 +52,7 @@ func TestSQLite_GetZettel(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := repo.FindByID(tc.id)
+			_, err := repo.FindByID(ctx, tc.id)
 			if err != tc.expectedErr {
 				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
 			}
@@ -88,7 +92,7 @@ func TestSQLite_UpdateZettel(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			z := tc.zettel()
-			if err := repo.Update(z); err != tc.expectedErr {
+			if err := repo.Update(ctx, z); err != tc.expectedErr {
 				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
 			}
 		})
@@ -119,11 +123,11 @@ func TestSQLite_AddLink(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			z := tc.setup()
-			if err := repo.Save(z); err != nil {
+			if err := repo.Save(ctx, z); err != nil {
 				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
 			}
 			// find the zettel to check if the link was added
-			z, err := repo.FindByID(z.ID())
+			z, err := repo.FindByID(ctx, z.ID())
 			if err != nil {
 				t.Error(err)
 			}
@@ -140,8 +144,272 @@ func createZettel(t *testing.T) zettel.Zettel {
 	if err != nil {
 		t.Error(err)
 	}
-	if err := repo.Save(z); err != nil {
+	if err := repo.Save(ctx, z); err != nil {
 		t.Error(err)
 	}
 	return z
 }
+
+func TestSQLite_FindReferencing(t *testing.T) {
+	title := uuid.NewString()
+
+	target, err := zettel.New(title, "content", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	referencing, err := zettel.New("title", "see [["+title+"#Heading]]", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spaced, err := zettel.New("title", "see [[ "+title+" ]]", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	similar, err := zettel.New("title", "see [["+title+" 2]]", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mentioning, err := zettel.New("title", "see "+title, zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveAll(ctx, []zettel.Zettel{target, referencing, spaced, similar, mentioning}); err != nil {
+		t.Fatal(err)
+	}
+
+	zettels, err := repo.FindReferencing(ctx, title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[uuid.UUID]bool{}
+	for _, z := range zettels {
+		found[z.ID()] = true
+	}
+	if len(zettels) != 2 || !found[referencing.ID()] || !found[spaced.ID()] {
+		t.Errorf("expected only %s and %s to reference %q, got %d zettels", referencing.ID(), spaced.ID(), title, len(zettels))
+	}
+}
+
+func TestSQLite_FindLinking(t *testing.T) {
+	target := createZettel(t)
+	linker := createZettel(t)
+	linker.Link(target.ID())
+	if err := repo.Save(ctx, linker); err != nil {
+		t.Fatal(err)
+	}
+
+	zettels, err := repo.FindLinking(ctx, target.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zettels) != 1 || zettels[0].ID() != linker.ID() {
+		t.Errorf("expected only %s to link to %s, got %d zettels", linker.ID(), target.ID(), len(zettels))
+	}
+}
+
+func TestSQLite_FindBlock(t *testing.T) {
+	z, err := zettel.New("title", "quoted ^quote", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, z); err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := repo.FindBlock(ctx, z.ID(), "quote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Content != "quoted" {
+		t.Errorf("expected block content %q, got %q", "quoted", block.Content)
+	}
+
+	z.SetBody("no blocks left")
+	if err := repo.Save(ctx, z); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindBlock(ctx, z.ID(), "quote"); err != zettel.ErrBlockNotFound {
+		t.Errorf("expected error %v, got %v", zettel.ErrBlockNotFound, err)
+	}
+}
+
+func TestSQLite_History(t *testing.T) {
+	first := createZettel(t)
+	second := createZettel(t)
+	for _, id := range []uuid.UUID{first.ID(), second.ID(), first.ID()} {
+		if err := repo.AddToHistory(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	visits, total, err := repo.FindHistory(ctx, shared.Page{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visits) != 2 || visits[0].Zettel.ID() != first.ID() || visits[1].Zettel.ID() != second.ID() {
+		t.Errorf("expected %s then %s, got %v", first.ID(), second.ID(), visits)
+	}
+	if total < 2 {
+		t.Errorf("expected at least 2 visits, got %d", total)
+	}
+
+	visits, _, err = repo.FindHistory(ctx, shared.Page{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visits) != 1 || visits[0].Zettel.ID() != second.ID() {
+		t.Errorf("expected %s, got %v", second.ID(), visits)
+	}
+}
+
+func TestSQLite_SearchPageByName(t *testing.T) {
+	name := uuid.NewString()
+	var zettels []zettel.Zettel
+	for i := range 3 {
+		z, err := zettel.New(fmt.Sprintf("%s %d", name, i), "content", zettel.Fleet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zettels = append(zettels, z)
+	}
+	// found by its alias, with its parts loaded along
+	aliased, err := zettel.New("title", "content", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliased.AddAlias(name)
+	aliased.Link(zettels[0].ID())
+	zettels = append(zettels, aliased)
+	// the times are stored to the millisecond, so they are set apart for
+	// the zettels to be found in this order
+	for i := range zettels {
+		zettels[i].SetCreated(time.Now().Add(time.Duration(i) * time.Second))
+	}
+	if err := repo.SaveAll(ctx, zettels); err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		test     string
+		page     shared.Page
+		expected []zettel.Zettel
+	}
+
+	testCases := []testCase{
+		{test: "First page", page: shared.Page{Limit: 2}, expected: zettels[:2]},
+		{test: "Last page", page: shared.Page{Limit: 2, Offset: 2}, expected: zettels[2:]},
+		{test: "Past the end", page: shared.Page{Limit: 2, Offset: 4}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			got, total, err := repo.SearchPageByName(ctx, strings.ToUpper(name), tc.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != len(zettels) {
+				t.Errorf("expected a total of %d, got %d", len(zettels), total)
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %d zettels, got %d", len(tc.expected), len(got))
+			}
+			for i := range got {
+				if got[i].ID() != tc.expected[i].ID() {
+					t.Errorf("expected %s at %d, got %s", tc.expected[i].ID(), i, got[i].ID())
+				}
+				if len(got[i].Links()) != len(tc.expected[i].Links()) || len(got[i].Aliases()) != len(tc.expected[i].Aliases()) {
+					t.Errorf("expected the links and aliases of %s, got %v and %v", got[i].ID(), got[i].Links(), got[i].Aliases())
+				}
+			}
+		})
+	}
+}
+
+func TestSQLite_Merge(t *testing.T) {
+	keep := createZettel(t)
+	absorb, err := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linker := createZettel(t)
+	linker.Link(absorb.ID())
+	if err := repo.SaveAll(ctx, []zettel.Zettel{absorb, linker}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keep.Absorb(absorb); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Merge(ctx, keep, absorb.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.FindByID(ctx, absorb.ID()); err != zettel.ErrZettelNotFound {
+		t.Errorf("expected error %v, got %v", zettel.ErrZettelNotFound, err)
+	}
+
+	z, err := repo.FindByTitle(ctx, absorb.Title())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.ID() != keep.ID() {
+		t.Errorf("expected the alias to resolve to %s, got %s", keep.ID(), z.ID())
+	}
+
+	linker, err = repo.FindByID(ctx, linker.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linker.Links()) != 1 || linker.Links()[0].To != keep.ID() {
+		t.Errorf("expected the link to be re-pointed to %s, got %v", keep.ID(), linker.Links())
+	}
+}
+
+func TestSQLite_FindVersions(t *testing.T) {
+	z := createZettel(t)
+
+	versions, err := repo.FindVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the times are stored to the millisecond
+	updated := z.Timestamp().Updated.Truncate(time.Millisecond)
+	if !versions[z.ID()].Equal(updated) {
+		t.Errorf("expected version %v, got %v", updated, versions[z.ID()])
+	}
+
+	if err := repo.Delete(ctx, z.ID()); err != nil {
+		t.Fatal(err)
+	}
+	versions, err = repo.FindVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := versions[z.ID()]; ok {
+		t.Errorf("expected no version for the deleted zettel %s", z.ID())
+	}
+}
+
+func TestSQLite_FindVersion(t *testing.T) {
+	z := createZettel(t)
+
+	updated, count, err := repo.FindVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the times are stored to the millisecond
+	if expected := z.Timestamp().Updated.Truncate(time.Millisecond); updated.Before(expected) {
+		t.Errorf("expected version %v or later, got %v", expected, updated)
+	}
+
+	if err := repo.Delete(ctx, z.ID()); err != nil {
+		t.Fatal(err)
+	}
+	_, after, err := repo.FindVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after != count-1 {
+		t.Errorf("expected %d zettels after the delete, got %d", count-1, after)
+	}
+}
@@ -0,0 +1,102 @@
+package zettel
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTransclusionDepth is how many levels of nested embeds are expanded.
+const DefaultTransclusionDepth = 3
+
+// embedRegex matches embeds such as ![[Title]] and ![[Title#Heading]].
+var embedRegex = regexp.MustCompile(`!(\[\[[^\[\]]+\]\])`)
+
+// Transclusion expands the embeds of a zettel, replacing each ![[Title]]
+// with the content of the zettel it names, ![[Title#Heading]] with the
+// section under the heading and ![[Title#^block-id]] with the block.
+type Transclusion struct {
+	// Find returns the zettel known by the given name.
+	Find func(name string) (Zettel, bool)
+	// Wrap marks the embedded content as coming from the referenced zettel.
+	Wrap func(ref Reference, content string) string
+	// MaxDepth is how many levels of nested embeds are expanded.
+	MaxDepth int
+}
+
+// Expand returns the body of the zettel with its embeds expanded. Embeds
+// that cannot be found, that would embed a zettel inside itself or that are
+// nested deeper than MaxDepth are left as plain wiki-links.
+func (t Transclusion) Expand(z Zettel) string {
+	return t.expand(StripFrontMatter(z.Content()), []uuid.UUID{z.ID()})
+}
+
+func (t Transclusion) expand(body string, stack []uuid.UUID) string {
+	lines := strings.Split(body, "\n")
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if isFence(trimmed) {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		lines[i] = embedRegex.ReplaceAllStringFunc(line, func(embed string) string {
+			link := embed[1:]
+			refs := ParseReferences(link)
+			if len(refs) != 1 || len(stack) > t.MaxDepth {
+				return link
+			}
+			ref := refs[0]
+
+			source, ok := t.Find(ref.Title)
+			if !ok || containsID(stack, source.ID()) {
+				return link
+			}
+			content, ok := embedded(source, ref.Anchor)
+			if !ok {
+				return link
+			}
+
+			content = t.expand(content, append(stack, source.ID()))
+			if t.Wrap != nil {
+				content = t.Wrap(ref, content)
+			}
+			return content
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// embedded returns the content of the zettel, or of the section or block the
+// anchor points to when one is given.
+func embedded(z Zettel, anchor string) (string, bool) {
+	body := StripFrontMatter(z.Content())
+	if anchor == "" {
+		return strings.TrimSpace(body), true
+	}
+	if id, ok := strings.CutPrefix(anchor, "^"); ok {
+		block, ok := z.Block(id)
+		return block.Content, ok
+	}
+
+	lines := strings.Split(body, "\n")
+	for _, section := range ParseSections(body) {
+		if section.Heading == anchor {
+			return strings.TrimSpace(strings.Join(lines[section.Start:section.End], "\n")), true
+		}
+	}
+	return "", false
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
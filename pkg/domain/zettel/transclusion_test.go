@@ -0,0 +1,101 @@
+package zettel_test
+
+import (
+	"testing"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestZettel_Transclusion(t *testing.T) {
+	zettels := map[string]zettel.Zettel{}
+	add := func(title, content string) zettel.Zettel {
+		z, err := zettel.New(title, content, zettel.Permanent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zettels[title] = z
+		return z
+	}
+
+	add("Leaf", "---\ntags: [x]\n---\nleaf body")
+	add("Sections", "intro\n# First\nfirst body\n# Second\nsecond body\n\nquoted ^quote")
+	add("Self", "before ![[Self]] after")
+	add("Ping", "ping ![[Pong]]")
+	add("Pong", "pong ![[Ping]]")
+	add("Deep1", "1 ![[Deep2]]")
+	add("Deep2", "2 ![[Deep3]]")
+	add("Deep3", "3 ![[Leaf]]")
+
+	transclusion := zettel.Transclusion{
+		Find: func(name string) (zettel.Zettel, bool) {
+			z, ok := zettels[name]
+			return z, ok
+		},
+		Wrap: func(ref zettel.Reference, content string) string {
+			return "{" + content + "}"
+		},
+		MaxDepth: 2,
+	}
+
+	type testCase struct {
+		test     string
+		content  string
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			test:     "should embed a whole zettel without its front matter",
+			content:  "see ![[Leaf]]",
+			expected: "see {leaf body}",
+		},
+		{
+			test:     "should embed a section",
+			content:  "![[Sections#First]]",
+			expected: "{# First\nfirst body}",
+		},
+		{
+			test:     "should embed a block",
+			content:  "![[Sections#^quote]]",
+			expected: "{quoted}",
+		},
+		{
+			test:     "should leave missing zettels and headings as links",
+			content:  "![[Missing]] ![[Sections#Missing]]",
+			expected: "[[Missing]] [[Sections#Missing]]",
+		},
+		{
+			test:     "should not expand embeds in code blocks",
+			content:  "```\n![[Leaf]]\n```",
+			expected: "```\n![[Leaf]]\n```",
+		},
+		{
+			test:     "should stop at cycles",
+			content:  "![[Ping]]",
+			expected: "{ping {pong [[Ping]]}}",
+		},
+		{
+			test:     "should stop at the depth limit",
+			content:  "![[Deep1]]",
+			expected: "{1 {2 [[Deep3]]}}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			z, err := zettel.New("Root", tc.content, zettel.Permanent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := transclusion.Expand(z); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("should not embed a zettel in itself", func(t *testing.T) {
+		if got := transclusion.Expand(zettels["Self"]); got != "before [[Self]] after" {
+			t.Errorf("expected the embed to be left as a link, got %q", got)
+		}
+	})
+}
@@ -1,18 +1,19 @@
 package zettel
 
 import (
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
 	"github.com/odas0r/zet/pkg/domain/shared/timestamp"
 )
 
 var (
-	ErrInvalidZettelKind = errors.New("invalid zettel kind")
-	ErrMissingValues     = errors.New("missing values")
-	ErrLinkAlreadyExists = errors.New("link already exists")
-	ErrLinkDoesNotExist  = errors.New("link does not exist")
+	ErrInvalidZettelKind = shared.Invalid("invalid zettel kind")
+	ErrMissingValues     = shared.Invalid("missing values")
+	ErrLinkAlreadyExists = shared.Conflict("link already exists")
+	ErrLinkDoesNotExist  = shared.NotFound("link does not exist")
+	ErrMergeSameZettel   = shared.Invalid("cannot merge a zettel into itself")
 )
 
 // Zettel is an aggregate root that represents a zettel in the domain
@@ -20,9 +21,11 @@ type Zettel struct {
 	id        uuid.UUID
 	content   *Content
 	kind      Kind
+	sequence  Sequence
 	timestamp timestamp.Timestamp
 
-	links []Link
+	links   []Link
+	aliases []string
 }
 
 func New(title, content string, kind Kind) (Zettel, error) {
@@ -41,6 +44,7 @@ func New(title, content string, kind Kind) (Zettel, error) {
 		kind:      kind,
 		timestamp: timestamp.New(),
 		links:     []Link{},
+		aliases:   []string{},
 	}, nil
 }
 
@@ -49,15 +53,19 @@ func (z *Zettel) ID() uuid.UUID                  { return z.id }
 func (z *Zettel) Title() string                  { return z.content.Title }
 func (z *Zettel) Content() string                { return z.content.Body }
 func (z *Zettel) Kind() Kind                     { return z.kind }
+func (z *Zettel) Sequence() Sequence             { return z.sequence }
 func (z *Zettel) Timestamp() timestamp.Timestamp { return z.timestamp }
 func (z *Zettel) Links() []Link                  { return z.links }
+func (z *Zettel) Aliases() []string              { return z.aliases }
 
 // Setters
 func (z *Zettel) SetID(id uuid.UUID)           { z.id = id }
 func (z *Zettel) SetKind(kind Kind)            { z.kind = kind }
+func (z *Zettel) SetSequence(seq Sequence)     { z.sequence = seq }
 func (z *Zettel) SetCreated(created time.Time) { z.timestamp.Created = created }
 func (z *Zettel) SetUpdated(updated time.Time) { z.timestamp.Updated = updated }
 func (z *Zettel) SetLinks(links []Link)        { z.links = links }
+func (z *Zettel) SetAliases(aliases []string)  { z.aliases = aliases }
 func (z *Zettel) SetTitle(title string) {
 	if z.content == nil {
 		z.content = &Content{}
@@ -71,6 +79,17 @@ func (z *Zettel) SetBody(body string) {
 	z.content.Body = body
 }
 
+// Rename changes the title of the zettel. References to the old title in
+// other zettels must be rewritten with ReplaceReferences.
+func (z *Zettel) Rename(title string) error {
+	if title == "" {
+		return ErrMissingValues
+	}
+	z.SetTitle(title)
+	z.timestamp = z.timestamp.Update()
+	return nil
+}
+
 func (z *Zettel) Link(to uuid.UUID) error {
 	for _, link := range z.links {
 		if link.To == to {
@@ -94,3 +113,37 @@ func (z *Zettel) RemoveLink(to uuid.UUID) error {
 	}
 	return ErrLinkDoesNotExist
 }
+
+// Absorb merges other into the zettel: its content is appended under a
+// heading with its title, its outgoing links are moved over and its title and
+// aliases become aliases, so references to other keep resolving.
+func (z *Zettel) Absorb(other Zettel) error {
+	if other.ID() == z.ID() {
+		return ErrMergeSameZettel
+	}
+
+	z.SetBody(z.Content() + "\n\n## " + other.Title() + "\n\n" + other.Content())
+
+	for _, link := range other.Links() {
+		if link.To == z.ID() {
+			continue
+		}
+		// links present in both zettels are kept once
+		if err := z.Link(link.To); err != nil && err != ErrLinkAlreadyExists {
+			return err
+		}
+	}
+	// links to the absorbed zettel now point to itself
+	if err := z.RemoveLink(other.ID()); err != nil && err != ErrLinkDoesNotExist {
+		return err
+	}
+
+	for _, name := range append([]string{other.Title()}, other.Aliases()...) {
+		if err := z.AddAlias(name); err != nil && err != ErrAliasAlreadyExists {
+			return err
+		}
+	}
+
+	z.timestamp = z.timestamp.Update()
+	return nil
+}
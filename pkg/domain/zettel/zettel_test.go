@@ -48,3 +48,30 @@ func TestZettel_NewZettel(t *testing.T) {
 		})
 	}
 }
+
+func TestZettel_Absorb(t *testing.T) {
+	keep, _ := zettel.New("keep", "keep content", zettel.Permanent)
+	absorb, _ := zettel.New("absorb", "absorb content", zettel.Fleet)
+	other, _ := zettel.New("other", "other content", zettel.Fleet)
+
+	absorb.Link(keep.ID())
+	absorb.Link(other.ID())
+	keep.Link(absorb.ID())
+
+	if err := keep.Absorb(absorb); err != nil {
+		t.Fatal(err)
+	}
+
+	if keep.Content() != "keep content\n\n## absorb\n\nabsorb content" {
+		t.Errorf("unexpected merged content %q", keep.Content())
+	}
+	if len(keep.Links()) != 1 || keep.Links()[0].To != other.ID() {
+		t.Errorf("expected a single link to %s, got %v", other.ID(), keep.Links())
+	}
+	if !keep.HasName("absorb") {
+		t.Error("expected the absorbed title to become an alias")
+	}
+	if err := keep.Absorb(keep); err != zettel.ErrMergeSameZettel {
+		t.Errorf("expected error %v, got %v", zettel.ErrMergeSameZettel, err)
+	}
+}
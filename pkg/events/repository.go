@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// repository notifies the watcher after each change, so that the changes
+// made through the server reach the subscribers at once.
+type repository struct {
+	zettel.Repository
+	watcher *Watcher
+}
+
+// Repository returns the repository, notifying the watcher of its changes.
+func (w *Watcher) Repository(repo zettel.Repository) zettel.Repository {
+	return &repository{Repository: repo, watcher: w}
+}
+
+// notified notifies the watcher when the change succeeded.
+func (r *repository) notified(err error) error {
+	if err == nil {
+		r.watcher.Notify()
+	}
+	return err
+}
+
+func (r *repository) Save(ctx context.Context, z zettel.Zettel) error {
+	return r.notified(r.Repository.Save(ctx, z))
+}
+
+func (r *repository) SaveAll(ctx context.Context, zettels []zettel.Zettel) error {
+	return r.notified(r.Repository.SaveAll(ctx, zettels))
+}
+
+func (r *repository) Split(ctx context.Context, z zettel.Zettel, parts []zettel.Zettel) error {
+	return r.notified(r.Repository.Split(ctx, z, parts))
+}
+
+func (r *repository) Merge(ctx context.Context, keep zettel.Zettel, absorbID uuid.UUID) error {
+	return r.notified(r.Repository.Merge(ctx, keep, absorbID))
+}
+
+func (r *repository) Update(ctx context.Context, z zettel.Zettel) error {
+	return r.notified(r.Repository.Update(ctx, z))
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.notified(r.Repository.Delete(ctx, id))
+}
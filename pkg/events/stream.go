@@ -0,0 +1,66 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// EventName is the name of the server-sent events of the zettels, which the
+// pages listen to, see js/events.js.
+const EventName = "zettel"
+
+// keepAlive is how often a comment is sent on an idle stream, so that
+// proxies do not close it.
+const keepAlive = 30 * time.Second
+
+// retry is how long the browser waits before it reconnects a lost stream.
+const retry = 5 * time.Second
+
+// Handler streams the events of the watcher as server-sent events, each
+// with the JSON of the event as data. The stream is not bound by the write
+// timeout of the server, and ends with the request or the watcher.
+func (w *Watcher) Handler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(rw)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		events := w.Subscribe(r.Context())
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-store")
+		// nginx would otherwise hold the events back
+		rw.Header().Set("X-Accel-Buffering", "no")
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprintf(rw, "retry: %d\n\n", retry.Milliseconds())
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					return
+				}
+				fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", EventName, data)
+			case <-ticker.C:
+				fmt.Fprint(rw, ": keep-alive\n\n")
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// DefaultInterval is how often the watcher looks for zettels changed outside
+// of the server, e.g. by the CLI or the editor sync.
+const DefaultInterval = 2 * time.Second
+
+// bufferSize is the number of events a subscriber may fall behind by before
+// it misses some.
+const bufferSize = 16
+
+// Watcher publishes the zettels created, updated and deleted to its
+// subscribers. It compares the versions of the zettels on every interval,
+// and at once when notified of a change made through the server.
+type Watcher struct {
+	repo     zettel.Repository
+	interval time.Duration
+	notify   chan struct{}
+
+	mu          sync.Mutex
+	subscribers map[chan zettel.Event]struct{}
+	closed      bool
+}
+
+func NewWatcher(repo zettel.Repository, interval time.Duration) *Watcher {
+	return &Watcher{
+		repo:        repo,
+		interval:    interval,
+		notify:      make(chan struct{}, 1),
+		subscribers: map[chan zettel.Event]struct{}{},
+	}
+}
+
+// Subscribe returns the events published until the context is done or the
+// watcher stops, when the channel is closed. A subscriber that does not keep
+// up misses events rather than holding back the others.
+func (w *Watcher) Subscribe(ctx context.Context) <-chan zettel.Event {
+	events := make(chan zettel.Event, bufferSize)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		close(events)
+		return events
+	}
+	w.subscribers[events] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if _, ok := w.subscribers[events]; ok {
+			delete(w.subscribers, events)
+			close(events)
+		}
+	}()
+	return events
+}
+
+// Notify makes the watcher look for changes now, without waiting for the
+// interval.
+func (w *Watcher) Notify() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+		// a check is already due
+	}
+}
+
+// Run watches the zettels until the context is done, then closes the
+// channels of the subscribers, so that their streams end with the server.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer w.close()
+
+	versions, err := w.repo.FindVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-w.notify:
+		}
+
+		current, err := w.repo.FindVersions(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// the database may be busy, e.g. with a sync, so the next
+			// check tries again
+			slog.WarnContext(ctx, "failed to look for changed zettels", "error", err)
+			continue
+		}
+		for _, event := range changes(versions, current) {
+			w.publish(event)
+		}
+		versions = current
+	}
+}
+
+// changes returns the events that led from the previous versions to the
+// current ones.
+func changes(previous, current map[uuid.UUID]time.Time) []zettel.Event {
+	var events []zettel.Event
+	for id, updated := range current {
+		before, ok := previous[id]
+		switch {
+		case !ok:
+			events = append(events, zettel.Event{Type: zettel.EventCreated, ID: id})
+		case !before.Equal(updated):
+			events = append(events, zettel.Event{Type: zettel.EventUpdated, ID: id})
+		}
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			events = append(events, zettel.Event{Type: zettel.EventDeleted, ID: id})
+		}
+	}
+	return events
+}
+
+func (w *Watcher) publish(event zettel.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for events := range w.subscribers {
+		select {
+		case events <- event:
+		default:
+			slog.Warn("dropped an event for a slow subscriber", "type", event.Type, "id", event.ID)
+		}
+	}
+}
+
+func (w *Watcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	for events := range w.subscribers {
+		delete(w.subscribers, events)
+		close(events)
+	}
+}
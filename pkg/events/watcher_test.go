@@ -0,0 +1,192 @@
+package events_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/events"
+)
+
+// fakeRepository holds the versions of the zettels in memory.
+type fakeRepository struct {
+	zettel.Repository
+
+	mu       sync.Mutex
+	versions map[uuid.UUID]time.Time
+}
+
+func (r *fakeRepository) FindVersions(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := map[uuid.UUID]time.Time{}
+	for id, updated := range r.versions {
+		versions[id] = updated
+	}
+	return versions, nil
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.versions, id)
+	return nil
+}
+
+func (r *fakeRepository) set(id uuid.UUID, updated time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions[id] = updated
+}
+
+// start runs a watcher that only looks for changes when notified.
+func start(t *testing.T, repo *fakeRepository) *events.Watcher {
+	watcher := events.NewWatcher(repo, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := watcher.Run(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// the first versions are read before any change is made
+	time.Sleep(10 * time.Millisecond)
+	return watcher
+}
+
+func receive(t *testing.T, events <-chan zettel.Event) zettel.Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("expected an event")
+		return zettel.Event{}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	updated := uuid.New()
+	deleted := uuid.New()
+	repo := &fakeRepository{versions: map[uuid.UUID]time.Time{
+		updated: time.Unix(1, 0),
+		deleted: time.Unix(1, 0),
+	}}
+	watcher := start(t, repo)
+	events := watcher.Subscribe(context.Background())
+
+	type testCase struct {
+		test     string
+		change   func()
+		expected zettel.Event
+	}
+
+	created := uuid.New()
+	testCases := []testCase{
+		{
+			test:     "A zettel is created",
+			change:   func() { repo.set(created, time.Unix(1, 0)) },
+			expected: zettel.Event{Type: zettel.EventCreated, ID: created},
+		},
+		{
+			test:     "A zettel is updated",
+			change:   func() { repo.set(updated, time.Unix(2, 0)) },
+			expected: zettel.Event{Type: zettel.EventUpdated, ID: updated},
+		},
+		{
+			test: "A zettel is deleted through the repository",
+			change: func() {
+				// the repository notifies the watcher itself
+				watcher.Repository(repo).Delete(context.Background(), deleted)
+			},
+			expected: zettel.Event{Type: zettel.EventDeleted, ID: deleted},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			tc.change()
+			watcher.Notify()
+			if event := receive(t, events); event != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, event)
+			}
+		})
+	}
+}
+
+func TestWatcher_Unsubscribe(t *testing.T) {
+	watcher := start(t, &fakeRepository{versions: map[uuid.UUID]time.Time{}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := watcher.Subscribe(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected no event")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the channel to be closed")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	repo := &fakeRepository{versions: map[uuid.UUID]time.Time{}}
+	watcher := start(t, repo)
+	srv := httptest.NewServer(watcher.Handler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if contentType := res.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expected an event stream, got %q", contentType)
+	}
+
+	id := uuid.New()
+	repo.set(id, time.Unix(1, 0))
+	watcher.Notify()
+
+	lines := bufio.NewScanner(res.Body)
+	var name, data string
+	for data == "" && lines.Scan() {
+		if v, ok := strings.CutPrefix(lines.Text(), "event: "); ok {
+			name = v
+		}
+		if v, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			data = v
+		}
+	}
+	if name != events.EventName {
+		t.Errorf("expected the event %q, got %q", events.EventName, name)
+	}
+	var event zettel.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (zettel.Event{Type: zettel.EventCreated, ID: id}); event != expected {
+		t.Errorf("expected %v, got %v", expected, event)
+	}
+}
@@ -0,0 +1,272 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoPath = errors.New("no path between the zettels")
+)
+
+// Common PageRank parameters.
+const (
+	DefaultDamping    = 0.85
+	DefaultIterations = 50
+)
+
+// Score is a node ranked by a centrality measure.
+type Score struct {
+	Node  Node
+	Value float64
+}
+
+// Degree ranks the nodes by their number of incoming and outgoing links.
+func (g *Graph) Degree() []Score {
+	degree := map[uuid.UUID]int{}
+	for _, e := range g.Edges {
+		degree[e.From]++
+		degree[e.To]++
+	}
+
+	scores := make([]Score, len(g.Nodes))
+	for i, n := range g.Nodes {
+		scores[i] = Score{Node: n, Value: float64(degree[n.ID])}
+	}
+	sortScores(scores)
+	return scores
+}
+
+// PageRank ranks the nodes by PageRank, so zettels linked from other well
+// linked zettels score higher. The scores add up to 1.
+func (g *Graph) PageRank(damping float64, iterations int) []Score {
+	n := len(g.Nodes)
+	if n == 0 {
+		return nil
+	}
+
+	index := map[uuid.UUID]int{}
+	for i, node := range g.Nodes {
+		index[node.ID] = i
+	}
+	out := make([][]int, n)
+	for _, e := range g.Edges {
+		out[index[e.From]] = append(out[index[e.From]], index[e.To])
+	}
+
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	for it := 0; it < iterations; it++ {
+		next := make([]float64, n)
+		// nodes without links spread their rank over every node
+		dangling := 0.0
+		for i, targets := range out {
+			if len(targets) == 0 {
+				dangling += rank[i]
+				continue
+			}
+			share := rank[i] / float64(len(targets))
+			for _, j := range targets {
+				next[j] += share
+			}
+		}
+		for i := range next {
+			next[i] = (1-damping)/float64(n) + damping*(next[i]+dangling/float64(n))
+		}
+		rank = next
+	}
+
+	scores := make([]Score, n)
+	for i, node := range g.Nodes {
+		scores[i] = Score{Node: node, Value: rank[i]}
+	}
+	sortScores(scores)
+	return scores
+}
+
+// Components returns the groups of nodes connected by links in either
+// direction, largest first.
+func (g *Graph) Components() [][]Node {
+	adjacent := g.undirected()
+	nodes := g.index()
+
+	seen := map[uuid.UUID]bool{}
+	var components [][]Node
+	for _, n := range g.Nodes {
+		if seen[n.ID] {
+			continue
+		}
+		seen[n.ID] = true
+
+		var component []Node
+		stack := []uuid.UUID{n.ID}
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			component = append(component, nodes[id])
+			for _, next := range adjacent[id] {
+				if !seen[next] {
+					seen[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		components = append(components, component)
+	}
+
+	sortGroups(components)
+	return components
+}
+
+// Communities detects groups of densely linked nodes by label propagation:
+// every node repeatedly takes the label most common among its neighbours
+// until the labels settle. Groups are returned largest first.
+func (g *Graph) Communities() [][]Node {
+	adjacent := g.undirected()
+
+	label := map[uuid.UUID]int{}
+	for i, n := range g.Nodes {
+		label[n.ID] = i
+	}
+
+	for changed, it := true, 0; changed && it < 100; it++ {
+		changed = false
+		for _, n := range g.Nodes {
+			counts := map[int]int{}
+			for _, next := range adjacent[n.ID] {
+				counts[label[next]]++
+			}
+
+			best, bestCount := label[n.ID], 0
+			for l, count := range counts {
+				// ties go to the smallest label, so the result is deterministic
+				if count > bestCount || (count == bestCount && l < best) {
+					best, bestCount = l, count
+				}
+			}
+			if bestCount > 0 && best != label[n.ID] {
+				label[n.ID] = best
+				changed = true
+			}
+		}
+	}
+
+	groups := map[int][]Node{}
+	var order []int
+	for _, n := range g.Nodes {
+		l := label[n.ID]
+		if _, ok := groups[l]; !ok {
+			order = append(order, l)
+		}
+		groups[l] = append(groups[l], n)
+	}
+
+	communities := make([][]Node, len(order))
+	for i, l := range order {
+		communities[i] = groups[l]
+	}
+	sortGroups(communities)
+	return communities
+}
+
+// ShortestPath returns the nodes on the shortest path from one node to
+// another, following links in either direction.
+func (g *Graph) ShortestPath(from, to uuid.UUID) ([]Node, error) {
+	if _, ok := g.Node(from); !ok {
+		return nil, ErrNoPath
+	}
+	adjacent := g.undirected()
+	nodes := g.index()
+
+	previous := map[uuid.UUID]uuid.UUID{from: uuid.Nil}
+	queue := []uuid.UUID{from}
+	for len(queue) > 0 && !hasKey(previous, to) {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacent[id] {
+			if !hasKey(previous, next) {
+				previous[next] = id
+				queue = append(queue, next)
+			}
+		}
+	}
+	if !hasKey(previous, to) {
+		return nil, ErrNoPath
+	}
+
+	var path []Node
+	for id := to; id != uuid.Nil; id = previous[id] {
+		path = append([]Node{nodes[id]}, path...)
+	}
+	return path, nil
+}
+
+// Stats summarises the shape of the graph.
+type Stats struct {
+	Nodes       int
+	Edges       int
+	Components  int
+	Isolated    int
+	Communities int
+}
+
+// Stats returns the summary of the graph.
+func (g *Graph) Stats() Stats {
+	stats := Stats{
+		Nodes:       len(g.Nodes),
+		Edges:       len(g.Edges),
+		Components:  len(g.Components()),
+		Communities: len(g.Communities()),
+	}
+	for _, score := range g.Degree() {
+		if score.Value == 0 {
+			stats.Isolated++
+		}
+	}
+	return stats
+}
+
+// Islands returns every component but the largest one, which holds the main
+// body of linked zettels.
+func (g *Graph) Islands() [][]Node {
+	components := g.Components()
+	if len(components) <= 1 {
+		return nil
+	}
+	return components[1:]
+}
+
+// undirected returns the neighbours of every node, ignoring link direction.
+func (g *Graph) undirected() map[uuid.UUID][]uuid.UUID {
+	adjacent := map[uuid.UUID][]uuid.UUID{}
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+		adjacent[e.To] = append(adjacent[e.To], e.From)
+	}
+	return adjacent
+}
+
+func (g *Graph) index() map[uuid.UUID]Node {
+	nodes := make(map[uuid.UUID]Node, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	return nodes
+}
+
+func hasKey(m map[uuid.UUID]uuid.UUID, key uuid.UUID) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func sortScores(scores []Score) {
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Value > scores[j].Value })
+}
+
+func sortGroups(groups [][]Node) {
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+}
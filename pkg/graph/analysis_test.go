@@ -0,0 +1,75 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/graph"
+)
+
+// newIslands returns a graph of a star of four zettels linking to a hub, a
+// pair of zettels linking to each other and a zettel without links.
+func newIslands(t *testing.T) (*graph.Graph, map[string]uuid.UUID) {
+	titles := []string{"hub", "s1", "s2", "s3", "s4", "p1", "p2", "alone"}
+	links := map[string]string{"s1": "hub", "s2": "hub", "s3": "hub", "s4": "hub", "p1": "p2"}
+
+	ids := map[string]uuid.UUID{}
+	zettels := make([]zettel.Zettel, len(titles))
+	for i, title := range titles {
+		z, err := zettel.New(title, "content", zettel.Fleet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zettels[i] = z
+		ids[title] = z.ID()
+	}
+	for i := range zettels {
+		if to, ok := links[titles[i]]; ok {
+			zettels[i].Link(ids[to])
+		}
+	}
+
+	return graph.New(zettels, map[uuid.UUID][]uuid.UUID{}), ids
+}
+
+func TestGraph_Centrality(t *testing.T) {
+	g, ids := newIslands(t)
+
+	if hub := g.PageRank(graph.DefaultDamping, graph.DefaultIterations)[0]; hub.Node.ID != ids["hub"] {
+		t.Errorf("expected hub to rank first by PageRank, got %s", hub.Node.Title)
+	}
+	if hub := g.Degree()[0]; hub.Node.ID != ids["hub"] || hub.Value != 4 {
+		t.Errorf("expected hub to rank first by degree with 4 links, got %s with %v", hub.Node.Title, hub.Value)
+	}
+}
+
+func TestGraph_Components(t *testing.T) {
+	g, _ := newIslands(t)
+
+	islands := g.Islands()
+	if len(islands) != 2 || len(islands[0]) != 2 || len(islands[1]) != 1 {
+		t.Errorf("expected the pair and the lone zettel to be islands, got %v", islands)
+	}
+
+	stats := g.Stats()
+	if stats.Components != 3 || stats.Isolated != 1 || stats.Communities != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g, ids := newIslands(t)
+
+	path, err := g.ShortestPath(ids["s1"], ids["s2"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 3 || path[1].ID != ids["hub"] {
+		t.Errorf("expected the path to go through hub, got %v", path)
+	}
+
+	if _, err := g.ShortestPath(ids["s1"], ids["alone"]); err != graph.ErrNoPath {
+		t.Errorf("expected error %v, got %v", graph.ErrNoPath, err)
+	}
+}
@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown graph format")
+)
+
+// Format is a file format the graph can be exported to.
+type Format string
+
+const (
+	DOT     Format = "dot"
+	GraphML Format = "graphml"
+	Mermaid Format = "mermaid"
+	JSON    Format = "json"
+)
+
+// Formats lists the supported export formats.
+var Formats = []Format{DOT, GraphML, Mermaid, JSON}
+
+// Export writes the graph to w in the given format.
+func (g *Graph) Export(w io.Writer, format Format) error {
+	switch format {
+	case DOT:
+		return g.WriteDOT(w)
+	case GraphML:
+		return g.WriteGraphML(w)
+	case Mermaid:
+		return g.WriteMermaid(w)
+	case JSON:
+		return g.WriteJSON(w)
+	}
+	return ErrUnknownFormat
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph zettelkasten {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [label=%q, kind=%q, workspaces=%q, tags=%q];\n",
+			n.ID.String(), n.Title, n.Kind, joinIDs(n.Workspaces), strings.Join(n.Tags, ","))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [created=%q, updated=%q];\n",
+			e.From.String(), e.To.String(), formatTime(e.Created), formatTime(e.Updated))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	// mermaid ids cannot contain dashes, so nodes are numbered instead
+	ids := map[uuid.UUID]string{}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(n.Title, `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.ID], label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	ID         uuid.UUID   `json:"id"`
+	Title      string      `json:"title"`
+	Kind       string      `json:"kind"`
+	Workspaces []uuid.UUID `json:"workspaces"`
+	Tags       []string    `json:"tags"`
+}
+
+type jsonEdge struct {
+	Source  uuid.UUID `json:"source"`
+	Target  uuid.UUID `json:"target"`
+	Created time.Time `json:"created_at"`
+	Updated time.Time `json:"updated_at"`
+}
+
+// WriteJSON writes the graph as a JSON object of nodes and edges.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	out := jsonGraph{
+		Nodes: []jsonNode{},
+		Edges: []jsonEdge{},
+	}
+	for _, n := range g.Nodes {
+		node := jsonNode{
+			ID:         n.ID,
+			Title:      n.Title,
+			Kind:       string(n.Kind),
+			Workspaces: n.Workspaces,
+			Tags:       n.Tags,
+		}
+		if node.Workspaces == nil {
+			node.Workspaces = []uuid.UUID{}
+		}
+		if node.Tags == nil {
+			node.Tags = []string{}
+		}
+		out.Nodes = append(out.Nodes, node)
+	}
+	for _, e := range g.Edges {
+		out.Edges = append(out.Edges, jsonEdge{
+			Source:  e.From,
+			Target:  e.To,
+			Created: e.Created,
+			Updated: e.Updated,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as a GraphML document.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "title", For: "node", AttrName: "title", AttrType: "string"},
+			{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+			{ID: "workspaces", For: "node", AttrName: "workspaces", AttrType: "string"},
+			{ID: "tags", For: "node", AttrName: "tags", AttrType: "string"},
+			{ID: "created", For: "edge", AttrName: "created_at", AttrType: "string"},
+			{ID: "updated", For: "edge", AttrName: "updated_at", AttrType: "string"},
+		},
+	}
+	doc.Graph.ID = "zettelkasten"
+	doc.Graph.EdgeDefault = "directed"
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID.String(),
+			Data: []graphMLData{
+				{Key: "title", Value: n.Title},
+				{Key: "kind", Value: string(n.Kind)},
+				{Key: "workspaces", Value: joinIDs(n.Workspaces)},
+				{Key: "tags", Value: strings.Join(n.Tags, ",")},
+			},
+		})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: e.From.String(),
+			Target: e.To.String(),
+			Data: []graphMLData{
+				{Key: "created", Value: formatTime(e.Created)},
+				{Key: "updated", Value: formatTime(e.Updated)},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func joinIDs(ids []uuid.UUID) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+	return strings.Join(s, ",")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// Node is a zettel in the graph.
+type Node struct {
+	ID         uuid.UUID
+	Title      string
+	Kind       zettel.Kind
+	Workspaces []uuid.UUID
+	Tags       []string
+}
+
+// Edge is a link from one zettel to another.
+type Edge struct {
+	From    uuid.UUID
+	To      uuid.UUID
+	Created time.Time
+	Updated time.Time
+}
+
+// Graph is the network of zettels formed by their links.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Filter selects the nodes kept in a graph. Empty fields match every node.
+type Filter struct {
+	WorkspaceID uuid.UUID
+	Kind        zettel.Kind
+	Tag         string
+}
+
+// New builds the graph of the given zettels. workspaces maps the id of each
+// zettel to the workspaces it belongs to. Links to zettels outside of the
+// given ones are left out.
+func New(zettels []zettel.Zettel, workspaces map[uuid.UUID][]uuid.UUID) *Graph {
+	g := &Graph{}
+
+	ids := map[uuid.UUID]bool{}
+	for _, z := range zettels {
+		ids[z.ID()] = true
+		g.Nodes = append(g.Nodes, Node{
+			ID:         z.ID(),
+			Title:      z.Title(),
+			Kind:       z.Kind(),
+			Workspaces: workspaces[z.ID()],
+			Tags:       z.Tags(),
+		})
+	}
+
+	for _, z := range zettels {
+		for _, link := range z.Links() {
+			if !ids[link.To] {
+				continue
+			}
+			g.Edges = append(g.Edges, Edge{
+				From:    link.From,
+				To:      link.To,
+				Created: link.Timestamp.Created,
+				Updated: link.Timestamp.Updated,
+			})
+		}
+	}
+
+	return g
+}
+
+// Node returns the node with the given id.
+func (g *Graph) Node(id uuid.UUID) (Node, bool) {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// Filter returns the subgraph of the nodes matching the filter.
+func (g *Graph) Filter(f Filter) *Graph {
+	return g.subgraph(func(n Node) bool {
+		return (f.WorkspaceID == uuid.Nil || contains(n.Workspaces, f.WorkspaceID)) &&
+			(f.Kind == "" || n.Kind == f.Kind) &&
+			(f.Tag == "" || contains(n.Tags, f.Tag))
+	})
+}
+
+// Neighbourhood returns the subgraph of the nodes at most k links away from
+// the root, following links in both directions.
+func (g *Graph) Neighbourhood(root uuid.UUID, k int) *Graph {
+	adjacent := g.undirected()
+
+	distance := map[uuid.UUID]int{}
+	if _, ok := g.Node(root); ok {
+		distance[root] = 0
+	}
+	queue := []uuid.UUID{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := distance[id]; !ok || distance[id] == k {
+			continue
+		}
+		for _, next := range adjacent[id] {
+			if _, seen := distance[next]; !seen {
+				distance[next] = distance[id] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return g.subgraph(func(n Node) bool {
+		_, ok := distance[n.ID]
+		return ok
+	})
+}
+
+func (g *Graph) subgraph(keep func(Node) bool) *Graph {
+	sub := &Graph{}
+
+	kept := map[uuid.UUID]bool{}
+	for _, n := range g.Nodes {
+		if keep(n) {
+			kept[n.ID] = true
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if kept[e.From] && kept[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+
+	return sub
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package graph_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/graph"
+)
+
+// newChain returns a graph of zettels linked one after the other, a -> b ->
+// c -> d, with b being the only permanent one.
+func newChain(t *testing.T) (*graph.Graph, []zettel.Zettel) {
+	var zettels []zettel.Zettel
+	for _, title := range []string{"a", "b", "c", "d"} {
+		kind := zettel.Fleet
+		if title == "b" {
+			kind = zettel.Permanent
+		}
+		z, err := zettel.New(title, "content", kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zettels = append(zettels, z)
+	}
+	for i := 0; i < len(zettels)-1; i++ {
+		zettels[i].Link(zettels[i+1].ID())
+	}
+	return graph.New(zettels, map[uuid.UUID][]uuid.UUID{}), zettels
+}
+
+func TestGraph_Neighbourhood(t *testing.T) {
+	type testCase struct {
+		test          string
+		depth         int
+		expectedNodes int
+		expectedEdges int
+	}
+
+	testCases := []testCase{
+		{test: "depth 0 only keeps the root", depth: 0, expectedNodes: 1, expectedEdges: 0},
+		{test: "depth 1 follows links both ways", depth: 1, expectedNodes: 3, expectedEdges: 2},
+		{test: "depth 2 reaches the whole chain", depth: 2, expectedNodes: 4, expectedEdges: 3},
+	}
+
+	g, zettels := newChain(t)
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			sub := g.Neighbourhood(zettels[1].ID(), tc.depth)
+			if len(sub.Nodes) != tc.expectedNodes || len(sub.Edges) != tc.expectedEdges {
+				t.Errorf("expected %d nodes and %d edges, got %d and %d",
+					tc.expectedNodes, tc.expectedEdges, len(sub.Nodes), len(sub.Edges))
+			}
+		})
+	}
+}
+
+func TestGraph_Filter(t *testing.T) {
+	g, _ := newChain(t)
+
+	sub := g.Filter(graph.Filter{Kind: zettel.Fleet})
+	if len(sub.Nodes) != 3 || len(sub.Edges) != 1 {
+		t.Errorf("expected 3 nodes and 1 edge, got %d and %d", len(sub.Nodes), len(sub.Edges))
+	}
+}
+
+func TestGraph_WriteMermaid(t *testing.T) {
+	g, _ := newChain(t)
+
+	var b bytes.Buffer
+	if err := g.Neighbourhood(g.Nodes[0].ID, 1).Export(&b, graph.Mermaid); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "graph LR\n  n0[\"a\"]\n  n1[\"b\"]\n  n0 --> n1\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
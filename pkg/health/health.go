@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/pressly/goose/v3"
+)
+
+// Timeout bounds the time the checks of a probe take together.
+const Timeout = 5 * time.Second
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var ErrPendingMigrations = errors.New("error: the database has pending migrations, run zet migrate up")
+
+// Check is a dependency the server needs to serve requests.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Report is the answer of a probe: ok when every check passed.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Run runs the checks concurrently.
+func Run(ctx context.Context, checks ...Check) Report {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: map[string]Result{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := check.Run(ctx)
+			result := Result{Status: StatusOK, Duration: time.Since(start).String()}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// Handler answers with the report of the checks, with 503 Service
+// Unavailable when one of them failed.
+func Handler(checks ...Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := Run(r.Context(), checks...)
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// Database checks that the database answers.
+func Database(db *sql.DB) Check {
+	return Check{Name: "database", Run: db.PingContext}
+}
+
+// Migrations checks that the database has every migration applied.
+func Migrations(db *sql.DB) Check {
+	return Check{Name: "migrations", Run: func(ctx context.Context) error {
+		provider, err := goose.NewProvider(goose.DialectSQLite3, db, nil)
+		if err != nil {
+			return err
+		}
+		pending, err := provider.HasPending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return ErrPendingMigrations
+		}
+		return nil
+	}}
+}
+
+// Workspaces checks that the directories of the workspaces still exist. The
+// paths are left out of the error, as the probes need no token.
+func Workspaces(repo workspace.Repository) Check {
+	return Check{Name: "workspaces", Run: func(ctx context.Context) error {
+		workspaces, err := repo.FindAllWorkspaces(ctx)
+		if err != nil {
+			return err
+		}
+		missing := 0
+		for _, w := range workspaces {
+			if info, err := os.Stat(w.Path()); err != nil || !info.IsDir() {
+				missing++
+			}
+		}
+		if missing > 0 {
+			return fmt.Errorf("error: %d of %d workspace directories are missing", missing, len(workspaces))
+		}
+		return nil
+	}}
+}
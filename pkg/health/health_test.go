@@ -0,0 +1,49 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/health"
+)
+
+func TestHandler(t *testing.T) {
+	ok := health.Check{Name: "ok", Run: func(ctx context.Context) error { return nil }}
+	failing := health.Check{Name: "failing", Run: func(ctx context.Context) error { return errors.New("down") }}
+
+	type testCase struct {
+		test           string
+		checks         []health.Check
+		expectedStatus int
+	}
+
+	testCases := []testCase{
+		{test: "Every check passes", checks: []health.Check{ok}, expectedStatus: http.StatusOK},
+		{test: "A check fails", checks: []health.Check{ok, failing}, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			health.Handler(tc.checks...)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, rec.Code)
+			}
+
+			var report health.Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Checks) != len(tc.checks) {
+				t.Errorf("expected a result per check, got %v", report.Checks)
+			}
+			if failed, ok := report.Checks["failing"]; ok && failed.Error != "down" {
+				t.Errorf("expected the error of the check, got %q", failed.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// started is part of every ETag, so that a new version of the server, which
+// may render the same zettels differently, does not answer with 304.
+var started = time.Now().UnixNano()
+
+// Key identifies a version of a response, from the times the resources it
+// shows were modified and anything else it depends on.
+type Key struct {
+	hash     hash.Hash
+	modified time.Time
+}
+
+func NewKey() *Key {
+	k := &Key{hash: sha256.New()}
+	fmt.Fprint(k.hash, started)
+	return k
+}
+
+// Modified adds the times resources of the response were modified. The
+// latest one is its Last-Modified time.
+func (k *Key) Modified(times ...time.Time) *Key {
+	for _, t := range times {
+		fmt.Fprintf(k.hash, "|%d", t.UnixNano())
+		if t.After(k.modified) {
+			k.modified = t
+		}
+	}
+	return k
+}
+
+// Add adds anything else the response depends on, like ids or whether it is
+// a whole page.
+func (k *Key) Add(parts ...string) *Key {
+	for _, part := range parts {
+		fmt.Fprintf(k.hash, "|%q", part)
+	}
+	return k
+}
+
+// ETag returns a weak ETag, as the response is the same once decompressed
+// but not byte for byte.
+func (k *Key) ETag() string {
+	return `W/"` + hex.EncodeToString(k.hash.Sum(nil)[:16]) + `"`
+}
+
+// NotModified sets the validators of the response and tells whether the
+// copy of the client is still fresh, answering 304 Not Modified then.
+// Responses that may not be stored, as in development mode, are always
+// sent whole.
+func NotModified(w http.ResponseWriter, r *http.Request, k *Key) bool {
+	if strings.Contains(w.Header().Get("Cache-Control"), "no-store") {
+		return false
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	etag := k.ETag()
+	w.Header().Set("ETag", etag)
+	if !k.modified.IsZero() {
+		w.Header().Set("Last-Modified", k.modified.UTC().Format(http.TimeFormat))
+	}
+	// stored by the browser, but checked with the server before each use
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if !matches(match, etag) {
+			return false
+		}
+	} else if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err != nil || k.modified.IsZero() || k.modified.Truncate(time.Second).After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// matches compares the ETags of If-None-Match weakly, ignoring W/.
+func matches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package httpcache_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/odas0r/zet/pkg/httpcache"
+)
+
+func TestNotModified(t *testing.T) {
+	updated := time.Date(2024, 7, 6, 14, 12, 8, 500, time.UTC)
+	key := func() *httpcache.Key { return httpcache.NewKey().Add("zettel").Modified(updated) }
+	etag := key().ETag()
+
+	type testCase struct {
+		test         string
+		header       string
+		value        string
+		cacheControl string
+		expected     bool
+	}
+
+	testCases := []testCase{
+		{test: "No validators", expected: false},
+		{test: "Same ETag", header: "If-None-Match", value: etag, expected: true},
+		{test: "Same ETag, strong", header: "If-None-Match", value: etag[2:], expected: true},
+		{test: "Other ETag", header: "If-None-Match", value: `W/"other"`, expected: false},
+		{test: "Not modified since", header: "If-Modified-Since", value: updated.Format(http.TimeFormat), expected: true},
+		{test: "Modified since", header: "If-Modified-Since", value: updated.Add(-time.Hour).Format(http.TimeFormat), expected: false},
+		{test: "Not stored", header: "If-None-Match", value: etag, cacheControl: "no-cache, no-store, must-revalidate", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set(tc.header, tc.value)
+			}
+			rec := httptest.NewRecorder()
+			if tc.cacheControl != "" {
+				rec.Header().Set("Cache-Control", tc.cacheControl)
+			}
+
+			if got := httpcache.NotModified(rec, r, key()); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+			if tc.expected && rec.Code != http.StatusNotModified {
+				t.Errorf("expected status %d, got %d", http.StatusNotModified, rec.Code)
+			}
+		})
+	}
+
+	if key().ETag() == httpcache.NewKey().Add("zettel").Modified(updated.Add(time.Second)).ETag() {
+		t.Errorf("expected the ETag to change with the modification time")
+	}
+}
@@ -0,0 +1,55 @@
+package httperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/odas0r/zet/pkg/auth"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/router/middleware"
+)
+
+// Target is the element of the layout that htmx swaps error messages into.
+const Target = "#errors"
+
+// ErrMethodNotAllowed is the error of the requests whose path exists, but
+// not with their method.
+var ErrMethodNotAllowed = errors.New("error: method not allowed")
+
+// Status returns the HTTP status code of an error, from its kind for the
+// errors of the domain.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated),
+		errors.Is(err, auth.ErrInvalidToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrForbidden),
+		errors.Is(err, middleware.ErrInvalidCSRFToken):
+		return http.StatusForbidden
+	case errors.Is(err, ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, shared.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, shared.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, shared.ErrInvalid):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// Message returns the message of an error to show to the user. Internal
+// errors are not explained, since they may reveal details of the server.
+func Message(err error) string {
+	if Status(err) == http.StatusInternalServerError {
+		return "Something went wrong, try again later"
+	}
+	return err.Error()
+}
+
+// Retarget sets the headers that make htmx swap an error message into the
+// Target element, instead of the element of the request.
+func Retarget(w http.ResponseWriter) {
+	w.Header().Set("HX-Retarget", Target)
+	w.Header().Set("HX-Reswap", "innerHTML")
+}
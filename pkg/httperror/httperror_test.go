@@ -0,0 +1,42 @@
+package httperror_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/auth"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/httperror"
+	"github.com/odas0r/zet/pkg/router/middleware"
+)
+
+func TestStatus(t *testing.T) {
+	type testCase struct {
+		test     string
+		err      error
+		expected int
+	}
+
+	testCases := []testCase{
+		{test: "not found", err: zettel.ErrZettelNotFound, expected: http.StatusNotFound},
+		{test: "not found in another package", err: workspace.ErrWorkspaceNotFound, expected: http.StatusNotFound},
+		{test: "wrapped conflict", err: fmt.Errorf("%w: %q", zettel.ErrAliasAlreadyExists, "alias"), expected: http.StatusConflict},
+		{test: "validation", err: zettel.ErrInvalidSequence, expected: http.StatusUnprocessableEntity},
+		{test: "zettel without a sequence", err: zettel.ErrMissingSequence, expected: http.StatusUnprocessableEntity},
+		{test: "unauthenticated", err: auth.ErrUnauthenticated, expected: http.StatusUnauthorized},
+		{test: "forbidden", err: auth.ErrForbidden, expected: http.StatusForbidden},
+		{test: "invalid CSRF token", err: middleware.ErrInvalidCSRFToken, expected: http.StatusForbidden},
+		{test: "internal", err: errors.New("disk full"), expected: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			if got := httperror.Status(tc.err); got != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
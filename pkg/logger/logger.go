@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var ErrInvalidFormat = errors.New("error: the log format must be text or json")
+
+// New returns a logger writing records of the level and above in the given
+// format. The records logged with a context carry the id of its request.
+func New(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch format {
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, ErrInvalidFormat
+	}
+	return slog.New(requestHandler{handler}), nil
+}
+
+// ParseLevel returns the level with the given name: debug, info, warn or
+// error.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(name))
+	return level, err
+}
+
+// requestHandler adds the request id of the context to the records.
+type requestHandler struct {
+	slog.Handler
+}
+
+func (h requestHandler) Handle(ctx context.Context, record slog.Record) error {
+	if request, ok := ctx.Value(contextKey{}).(*Request); ok {
+		record.AddAttrs(slog.String("request_id", request.ID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestHandler) WithGroup(name string) slog.Handler {
+	return requestHandler{h.Handler.WithGroup(name)}
+}
+
+type contextKey struct{}
+
+// Request identifies a request in the logs, and adds up the queries it ran.
+type Request struct {
+	ID string
+
+	mu      sync.Mutex
+	queries int
+	dbTime  time.Duration
+}
+
+// NewRequestID returns a random id for a request.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequest returns a context carrying the request with the given id.
+func WithRequest(ctx context.Context, id string) (context.Context, *Request) {
+	request := &Request{ID: id}
+	return context.WithValue(ctx, contextKey{}, request), request
+}
+
+// FromContext returns the request the context belongs to.
+func FromContext(ctx context.Context) (*Request, bool) {
+	request, ok := ctx.Value(contextKey{}).(*Request)
+	return request, ok
+}
+
+// AddQuery counts a query that took the given time.
+func (r *Request) AddQuery(duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+	r.dbTime += duration
+}
+
+// Queries returns the number of queries of the request, and the time they
+// took.
+func (r *Request) Queries() (int, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queries, r.dbTime
+}
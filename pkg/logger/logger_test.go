@@ -0,0 +1,41 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/odas0r/zet/pkg/logger"
+)
+
+func TestLogger_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := logger.New(&buf, logger.FormatJSON, slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, request := logger.WithRequest(context.Background(), "abc")
+	request.AddQuery(time.Millisecond)
+	request.AddQuery(2 * time.Millisecond)
+	l.InfoContext(ctx, "request")
+	l.DebugContext(ctx, "below the level")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["request_id"] != "abc" {
+		t.Errorf("expected the request id of the context, got %v", record["request_id"])
+	}
+	if queries, dbTime := request.Queries(); queries != 2 || dbTime != 3*time.Millisecond {
+		t.Errorf("expected 2 queries taking 3ms, got %d taking %s", queries, dbTime)
+	}
+
+	if _, err := logger.New(&buf, "xml", slog.LevelInfo); err != logger.ErrInvalidFormat {
+		t.Errorf("expected error %v, got %v", logger.ErrInvalidFormat, err)
+	}
+}
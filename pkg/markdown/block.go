@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// BlockID returns the id of the HTML element of a block, so wiki-links to
+// [[Title#^block-id]] can point to it.
+func BlockID(id string) string {
+	return "block-" + id
+}
+
+// blocks extends goldmark with ^block-id anchors: the anchor is removed from
+// the text and set as the id of its paragraph or list item.
+type blocks struct{}
+
+func (blocks) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(util.Prioritized(blockTransformer{}, 100)))
+}
+
+type blockTransformer struct{}
+
+func (blockTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var anchored []ast.Node
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && (n.Kind() == ast.KindParagraph || n.Kind() == ast.KindTextBlock) {
+			anchored = append(anchored, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range anchored {
+		last, ok := n.LastChild().(*ast.Text)
+		if !ok {
+			continue
+		}
+		m := zettel.BlockRegex.FindSubmatchIndex(last.Segment.Value(source))
+		if m == nil {
+			continue
+		}
+		id := string(last.Segment.Value(source)[m[2]:m[3]])
+		last.Segment = last.Segment.WithStop(last.Segment.Start + m[0])
+
+		// the items of tight lists hold their text directly, in a text block
+		target := n
+		if n.Kind() == ast.KindTextBlock && n.Parent() != nil && n.Parent().Kind() == ast.KindListItem {
+			target = n.Parent()
+		}
+		target.SetAttributeString("id", []byte(BlockID(id)))
+	}
+}
@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Resolver returns the URL of the zettel a wiki-link points to, or false
+// when no zettel is known by its title.
+type Resolver func(ref zettel.Reference) (string, bool)
+
+// policy keeps the markup markdown produces, plus the languages of code
+// blocks, the checkboxes of task lists, the htmx attributes of wiki-links and
+// the transclusion markers.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("hx-get").Matching(regexp.MustCompile(`^/workspaces/[0-9a-f-]+/zettels/[0-9a-f-]+$`)).OnElements("a")
+	p.AllowAttrs("hx-target").Matching(regexp.MustCompile(`^#content$`)).OnElements("a")
+	p.AllowAttrs("hx-push-url").Matching(regexp.MustCompile(`^true$`)).OnElements("a")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^wiki-link(-missing)?$`)).OnElements("a", "span")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^transclusion$`)).OnElements("div")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^transclusion-source$`)).OnElements("small")
+	return p
+}()
+
+// Render converts the markdown body of a zettel to sanitized HTML. Tables,
+// task lists and strikethrough are supported, wiki-links become links to the
+// zettels resolve finds for them and ^block-id anchors become element ids.
+func Render(body string, resolve Resolver) (string, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM, &wikiLinks{resolve: resolve}, blocks{}),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// raw HTML is kept and left to the sanitizer
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{used: map[string]bool{}}))
+
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(zettel.StripFrontMatter(body)), &buf, parser.WithContext(ctx)); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
+
+// HeadingID returns the id of the HTML element of a heading, so wiki-links
+// to [[Title#Heading]] can point to it.
+func HeadingID(heading string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(heading) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// headingIDs generates the ids of headings with HeadingID, numbering the
+// repeated ones.
+type headingIDs struct {
+	used map[string]bool
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	id := HeadingID(string(value))
+	if id == "" {
+		id = "heading"
+	}
+	unique := id
+	for i := 1; ids.used[unique]; i++ {
+		unique = id + "-" + strconv.Itoa(i)
+	}
+	ids.used[unique] = true
+	return []byte(unique)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
@@ -0,0 +1,147 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/markdown"
+)
+
+func resolve(ref zettel.Reference) (string, bool) {
+	if ref.Title != "Known" {
+		return "", false
+	}
+	return "/workspaces/1/zettels/2", true
+}
+
+func TestMarkdown_Render(t *testing.T) {
+	type testCase struct {
+		test     string
+		body     string
+		contains []string
+		excludes []string
+	}
+
+	testCases := []testCase{
+		{
+			test:     "should render headings with ids",
+			body:     "# My Heading",
+			contains: []string{`<h1 id="my-heading">My Heading</h1>`},
+		},
+		{
+			test:     "should render code blocks",
+			body:     "```go\nfmt.Println(\"[[Known]]\")\n```",
+			contains: []string{`<code class="language-go">`, "[[Known]]"},
+			excludes: []string{"wiki-link"},
+		},
+		{
+			test:     "should render tables",
+			body:     "| a | b |\n|---|---|\n| 1 | 2 |",
+			contains: []string{"<table>", "<td>1</td>"},
+		},
+		{
+			test:     "should render task lists",
+			body:     "- [x] done\n- [ ] todo",
+			contains: []string{`<input checked="" disabled="" type="checkbox"`, `<input disabled="" type="checkbox"`},
+		},
+		{
+			test: "should turn wiki-links into htmx links",
+			body: "see [[Known#Some Part|this]]",
+			contains: []string{
+				`href="/workspaces/1/zettels/2#some-part"`,
+				`hx-get="/workspaces/1/zettels/2"`,
+				`hx-target="#content"`,
+				">this</a>",
+			},
+		},
+		{
+			test:     "should link to blocks",
+			body:     "see [[Known#^quote]]",
+			contains: []string{`href="/workspaces/1/zettels/2#block-quote"`},
+		},
+		{
+			test:     "should anchor paragraphs and list items to their block ids",
+			body:     "a long\nparagraph ^para\n\n- item ^item\n- other",
+			contains: []string{`<p id="block-para">a long` + "\n" + `paragraph</p>`, `<li id="block-item">item</li>`},
+			excludes: []string{"^para", "^item"},
+		},
+		{
+			test:     "should mark unresolved wiki-links",
+			body:     "see [[Unknown]]",
+			contains: []string{`<span class="wiki-link-missing">Unknown</span>`},
+		},
+		{
+			test:     "should strip scripts and event handlers",
+			body:     "<script>alert(1)</script>\n\n<p onclick=\"alert(1)\">hi</p>",
+			contains: []string{"<p>hi</p>"},
+			excludes: []string{"<script", "onclick"},
+		},
+		{
+			test:     "should skip the front matter",
+			body:     "---\naliases: [ZK]\n---\nbody",
+			contains: []string{"<p>body</p>"},
+			excludes: []string{"aliases"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			html, err := markdown.Render(tc.body, resolve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(html, s) {
+					t.Errorf("expected %q in %q", s, html)
+				}
+			}
+			for _, s := range tc.excludes {
+				if strings.Contains(html, s) {
+					t.Errorf("expected no %q in %q", s, html)
+				}
+			}
+		})
+	}
+}
+
+// TestMarkdown_RenderBlocks checks that every block the zettel has is
+// rendered with its anchor, so that the links to it land.
+func TestMarkdown_RenderBlocks(t *testing.T) {
+	body := "a paragraph ^para\n\n- item ^item-1\n\n1. step ^step\t\n\nnot^anchor"
+	html, err := markdown.Render(body, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := zettel.ParseBlocks(body)
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d", len(blocks))
+	}
+	for _, b := range blocks {
+		if id := `id="` + markdown.BlockID(b.ID) + `"`; !strings.Contains(html, id) {
+			t.Errorf("expected %s in %q", id, html)
+		}
+	}
+	if !strings.Contains(html, "not^anchor") {
+		t.Errorf("expected the text without an anchor as it is, got %q", html)
+	}
+}
+
+func TestMarkdown_WrapHTML(t *testing.T) {
+	body := "before\n" + markdown.WrapHTML(zettel.Reference{Title: "Known", Anchor: "Part"}, "**embedded**") + "\nafter"
+
+	html, err := markdown.Render(body, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<div class="transclusion">`,
+		`<small class="transclusion-source">From <a class="wiki-link" href="/workspaces/1/zettels/2#part"`,
+		"<strong>embedded</strong>",
+	} {
+		if !strings.Contains(html, s) {
+			t.Errorf("expected %q in %q", s, html)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// WrapHTML marks an embedded content for Render: it is boxed in a
+// transclusion block headed by a link to its source.
+func WrapHTML(ref zettel.Reference, content string) string {
+	source := zettel.Reference{Title: ref.Title, Anchor: ref.Anchor}
+	// the blank lines let the content inside the block be read as markdown
+	return "\n\n<div class=\"transclusion\">\n\n" +
+		"<small class=\"transclusion-source\">From " + source.String() + "</small>\n\n" +
+		content + "\n\n</div>\n\n"
+}
+
+// WrapQuote marks an embedded content in plain markdown, as a block quote
+// that starts with a link to its source.
+func WrapQuote(ref zettel.Reference, content string) string {
+	source := zettel.Reference{Title: ref.Title, Anchor: ref.Anchor}
+	lines := append([]string{"From " + source.String(), ""}, strings.Split(content, "\n")...)
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
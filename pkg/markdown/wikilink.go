@@ -0,0 +1,104 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var wikiLinkRegex = regexp.MustCompile(`^\[\[[^\[\]]+\]\]`)
+
+// KindWikiLink is the kind of WikiLink nodes.
+var KindWikiLink = ast.NewNodeKind("WikiLink")
+
+// WikiLink is a [[Title]] link to another zettel.
+type WikiLink struct {
+	ast.BaseInline
+	Reference zettel.Reference
+}
+
+func (n *WikiLink) Kind() ast.NodeKind { return KindWikiLink }
+
+func (n *WikiLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Title":  n.Reference.Title,
+		"Anchor": n.Reference.Anchor,
+		"Label":  n.Reference.Label,
+	}, nil)
+}
+
+// wikiLinks extends goldmark with wiki-links.
+type wikiLinks struct {
+	resolve Resolver
+}
+
+func (e *wikiLinks) Extend(m goldmark.Markdown) {
+	// before the link parser, which also starts at "["
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(wikiLinkParser{}, 199)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&wikiLinkRenderer{resolve: e.resolve}, 199)))
+}
+
+type wikiLinkParser struct{}
+
+func (wikiLinkParser) Trigger() []byte { return []byte{'['} }
+
+func (wikiLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	match := wikiLinkRegex.Find(line)
+	if match == nil {
+		return nil
+	}
+	refs := zettel.ParseReferences(string(match))
+	if len(refs) != 1 {
+		return nil
+	}
+	block.Advance(len(match))
+	return &WikiLink{Reference: refs[0]}
+}
+
+type wikiLinkRenderer struct {
+	resolve Resolver
+}
+
+func (r *wikiLinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindWikiLink, r.render)
+}
+
+func (r *wikiLinkRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	ref := node.(*WikiLink).Reference
+
+	label := ref.Label
+	if label == "" {
+		label = ref.Title
+	}
+
+	href, ok := "", false
+	if r.resolve != nil {
+		href, ok = r.resolve(ref)
+	}
+	if !ok {
+		fmt.Fprintf(w, `<span class="wiki-link-missing">%s</span>`, html.EscapeString(label))
+		return ast.WalkSkipChildren, nil
+	}
+
+	target := href
+	if id := ref.BlockID(); id != "" {
+		target += "#" + BlockID(id)
+	} else if ref.Anchor != "" {
+		target += "#" + HeadingID(ref.Anchor)
+	}
+	fmt.Fprintf(w, `<a class="wiki-link" href="%s" hx-get="%s" hx-target="#content" hx-push-url="true">%s</a>`,
+		html.EscapeString(target), html.EscapeString(href), html.EscapeString(label))
+	return ast.WalkSkipChildren, nil
+}
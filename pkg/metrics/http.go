@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/odas0r/zet/pkg/router"
+)
+
+// HTTP counts the requests of each route and how long they take.
+type HTTP struct {
+	requests *Counter
+	duration *Histogram
+}
+
+// NewHTTP returns the request metrics, registered in the registry.
+func NewHTTP(registry *Registry) *HTTP {
+	h := &HTTP{
+		requests: NewCounter("zet_http_requests_total",
+			"Requests served, by route and status.", "method", "route", "status"),
+		duration: NewHistogram("zet_http_request_duration_seconds",
+			"Time to serve a request, by route.", DefaultBuckets, "method", "route"),
+	}
+	registry.Register(h.requests, h.duration)
+	return h
+}
+
+// statusRecorder keeps the status written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WithMetrics records the requests under the pattern of their route, so that
+// the paths with ids add up together.
+func (h *HTTP) WithMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		path := "unknown"
+		if route, ok := router.CurrentRoute(r); ok {
+			path = route.Path
+		}
+		h.requests.Inc(r.Method, path, strconv.Itoa(recorder.status))
+		h.duration.Observe(time.Since(start).Seconds(), r.Method, path)
+	})
+}
@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the
+// request latency histograms.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector writes its metrics in the Prometheus text format.
+type Collector interface {
+	Collect(ctx context.Context, w io.Writer) error
+}
+
+// Registry holds the collectors served by the metrics endpoint.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// WriteTo writes the metrics of every collector, in the order they were
+// registered.
+func (r *Registry) WriteTo(ctx context.Context, w io.Writer) error {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		if err := c.Collect(ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// written to a buffer first, so a failing collector gives an error
+		// instead of half the metrics
+		var buf bytes.Buffer
+		if err := r.WriteTo(req.Context(), &buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		buf.WriteTo(w)
+	}
+}
+
+// metric holds what every kind of metric has: its name, help and the names
+// of its labels.
+type metric struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (m metric) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, kind)
+}
+
+// key joins the label values of a series, to index it.
+func (m metric) key(values []string) string {
+	if len(values) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", m.name, len(m.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// format returns the labels of a series, followed by the extra ones.
+func (m metric) format(key string, extra ...string) string {
+	var pairs []string
+	if len(m.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, m.labels[i]+"="+strconv.Quote(value))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+"="+strconv.Quote(extra[i+1]))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Counter is a value that only goes up, with a series per set of label
+// values.
+type Counter struct {
+	metric
+	mu     sync.Mutex
+	series map[string]float64
+}
+
+func NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{
+		metric: metric{name: name, help: help, labels: labels},
+		series: map[string]float64{},
+	}
+}
+
+// Inc adds one to the series with the given label values.
+func (c *Counter) Inc(values ...string) {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.series[key]++
+}
+
+func (c *Counter) Collect(ctx context.Context, w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.header(w, "counter")
+	for _, key := range sortedKeys(c.series) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.format(key), formatFloat(c.series[key]))
+	}
+	return nil
+}
+
+// Histogram counts the observed values in buckets, with a series per set of
+// label values.
+type Histogram struct {
+	metric
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{
+		metric:  metric{name: name, help: help, labels: labels},
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+}
+
+// Observe adds the value to the series with the given label values.
+func (h *Histogram) Observe(value float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *Histogram) Collect(ctx context.Context, w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.header(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.format(key, "le", formatFloat(bound)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.format(key, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.format(key), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.format(key), s.count)
+	}
+	return nil
+}
+
+// GaugeFunc is a value read when the metrics are collected.
+type GaugeFunc struct {
+	metric
+	value func(ctx context.Context) (float64, error)
+}
+
+func NewGaugeFunc(name, help string, value func(ctx context.Context) (float64, error)) *GaugeFunc {
+	return &GaugeFunc{
+		metric: metric{name: name, help: help},
+		value:  value,
+	}
+}
+
+func (g *GaugeFunc) Collect(ctx context.Context, w io.Writer) error {
+	value, err := g.value(ctx)
+	if err != nil {
+		return fmt.Errorf("metrics: %s: %w", g.name, err)
+	}
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(value))
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
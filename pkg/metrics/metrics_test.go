@@ -0,0 +1,69 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/metrics"
+	"github.com/odas0r/zet/pkg/router"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := metrics.NewCounter("requests_total", "Requests.", "status")
+	duration := metrics.NewHistogram("duration_seconds", "Duration.", []float64{.1, 1})
+	registry.Register(requests, duration, metrics.NewGaugeFunc("zettels", "Zettels.", func(ctx context.Context) (float64, error) {
+		return 3, nil
+	}))
+
+	requests.Inc("200")
+	requests.Inc("200")
+	requests.Inc("404")
+	duration.Observe(.05)
+	duration.Observe(.5)
+
+	var b strings.Builder
+	if err := registry.WriteTo(context.Background(), &b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE requests_total counter",
+		`requests_total{status="200"} 2`,
+		`requests_total{status="404"} 1`,
+		"# TYPE duration_seconds histogram",
+		`duration_seconds_bucket{le="0.1"} 1`,
+		`duration_seconds_bucket{le="1"} 2`,
+		`duration_seconds_bucket{le="+Inf"} 2`,
+		"duration_seconds_sum 0.55",
+		"duration_seconds_count 2",
+		"# TYPE zettels gauge",
+		"zettels 3",
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("expected the line %q, got\n%s", line, b.String())
+		}
+	}
+}
+
+func TestHTTP_WithMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	httpMetrics := metrics.NewHTTP(registry)
+
+	r := router.New()
+	r.HandleFunc("GET /notes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}, httpMetrics.WithMetrics)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/notes/1", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/notes/2", nil))
+
+	var b strings.Builder
+	registry.WriteTo(context.Background(), &b)
+	if want := `zet_http_requests_total{method="GET",route="/notes/{id}",status="404"} 2`; !strings.Contains(b.String(), want) {
+		t.Errorf("expected the requests under the pattern of the route, got\n%s", b.String())
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Name returns the name of the function that built the middleware, such as
+// WithLogger or WithMethods, to tell which middleware a route goes through.
+// It is empty for a middleware that does nothing.
+func Name(m Middleware) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(m).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	// middleware built by a function, like WithMethods("GET"), is a closure
+	// named after it: WithMethods.func1
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(name, "-fm")
+	name = name[strings.LastIndex(name, ".")+1:]
+	if name == "emptyMiddleware" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/odas0r/zet/pkg/view"
+)
+
+// CSRFCookie holds the CSRF token of a browser session.
+const CSRFCookie = "zet_csrf"
+
+// ErrInvalidCSRFToken is the error of the requests that change something
+// without the CSRF token of the session.
+var ErrInvalidCSRFToken = errors.New("error: invalid CSRF token, reload the page and try again")
+
+// WithCSRF rejects the requests that change something without the CSRF
+// token of the session, which another site cannot read, answering them with
+// deny. The token is put in the context of the request, for the layout to
+// hand it to the forms and htmx requests of the page.
+func WithCSRF(deny func(w http.ResponseWriter, r *http.Request, err error)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var token string
+			if cookie, err := r.Cookie(CSRFCookie); err == nil {
+				token = cookie.Value
+			}
+			if token == "" {
+				b := make([]byte, 32)
+				if _, err := rand.Read(b); err != nil {
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+				token = base64.RawURLEncoding.EncodeToString(b)
+				http.SetCookie(w, &http.Cookie{
+					Name:     CSRFCookie,
+					Value:    token,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			r = r.WithContext(view.WithCSRFToken(r.Context(), token))
+
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			default:
+				// browsers do not add credentials to the Authorization
+				// header of requests made by other sites, so those are not
+				// forged
+				if r.Header.Get("Authorization") != "" {
+					break
+				}
+				sent := r.Header.Get(view.CSRFHeader)
+				if sent == "" {
+					sent = r.PostFormValue(view.CSRFField)
+				}
+				if subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+					deny(w, r, ErrInvalidCSRFToken)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/router/middleware"
+	"github.com/odas0r/zet/pkg/view"
+)
+
+func TestWithCSRF(t *testing.T) {
+	var token string
+	var denied error
+	deny := func(w http.ResponseWriter, r *http.Request, err error) {
+		// the page of the error gets the token too
+		token = view.CSRFToken(r.Context())
+		denied = err
+		w.WriteHeader(http.StatusForbidden)
+	}
+	handler := middleware.WithCSRF(deny)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = view.CSRFToken(r.Context())
+	}))
+
+	// the first request gets the token of the session
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != middleware.CSRFCookie {
+		t.Fatalf("expected the %s cookie, got %v", middleware.CSRFCookie, cookies)
+	}
+	if token == "" || token != cookies[0].Value {
+		t.Fatalf("expected the token of the cookie in the context, got %q", token)
+	}
+	session := cookies[0]
+
+	type testCase struct {
+		test     string
+		method   string
+		header   string
+		form     string
+		bearer   bool
+		cookie   *http.Cookie
+		expected int
+	}
+
+	testCases := []testCase{
+		{test: "GET without token", method: http.MethodGet, cookie: session, expected: http.StatusOK},
+		{test: "POST without token", method: http.MethodPost, cookie: session, expected: http.StatusForbidden},
+		{test: "POST with form token", method: http.MethodPost, form: session.Value, cookie: session, expected: http.StatusOK},
+		{test: "DELETE with header token", method: http.MethodDelete, header: session.Value, cookie: session, expected: http.StatusOK},
+		{test: "DELETE with wrong token", method: http.MethodDelete, header: "wrong", cookie: session, expected: http.StatusForbidden},
+		{test: "POST without session", method: http.MethodPost, form: session.Value, expected: http.StatusForbidden},
+		{test: "POST with bearer token", method: http.MethodPost, bearer: true, cookie: session, expected: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			form := url.Values{}
+			if tc.form != "" {
+				form.Set(view.CSRFField, tc.form)
+			}
+			r := httptest.NewRequest(tc.method, "/", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.header != "" {
+				r.Header.Set(view.CSRFHeader, tc.header)
+			}
+			if tc.bearer {
+				r.Header.Set("Authorization", "Bearer token")
+			}
+			if tc.cookie != nil {
+				r.AddCookie(tc.cookie)
+			}
+
+			token, denied = "", nil
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+			if rec.Code != tc.expected {
+				t.Errorf("expected status %d, got %d", tc.expected, rec.Code)
+			}
+			if tc.expected == http.StatusForbidden && !errors.Is(denied, middleware.ErrInvalidCSRFToken) {
+				t.Errorf("expected error %v, got %v", middleware.ErrInvalidCSRFToken, denied)
+			}
+			if token == "" {
+				t.Error("expected the token in the context")
+			}
+		})
+	}
+}
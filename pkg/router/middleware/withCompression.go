@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the size under which a response is sent as it is, as
+// compressing it would save little.
+const minCompressSize = 1024
+
+var compressibleTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"image/svg+xml",
+}
+
+var (
+	gzipWriters  = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	flateWriters = sync.Pool{New: func() any {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	}}
+)
+
+// WithCompression compresses the responses with gzip or deflate, whichever
+// the client accepts, gzip first. Small responses, responses that are not
+// text, responses already encoded and parts of a response, whose ranges are
+// of the uncompressed body, are sent as they are.
+func WithCompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the encoding to compress with, or "" when the
+// client accepts neither.
+func negotiateEncoding(header string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, _ = strconv.ParseFloat(v, 64)
+		}
+		accepted[strings.ToLower(name)] = q > 0
+	}
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter holds back the start of the response, until it knows
+// whether compressing it is worth it.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+	// buf holds the start of the body, until it is long enough to compress
+	buf         []byte
+	writer      io.WriteCloser
+	wroteHeader bool
+	decided     bool
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+	// a response without a body goes straight out
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.decided = true
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.writer != nil {
+			return w.writer.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= minCompressSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide starts the response, compressed or not, and writes what was held
+// back.
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	partial := w.status == http.StatusPartialContent || header.Get("Content-Range") != ""
+	if len(w.buf) >= minCompressSize && !partial && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		// the ETag identifies the uncompressed body
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.writer = w.compressor()
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if w.writer != nil {
+		_, err := w.writer.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+func (w *compressWriter) compressor() io.WriteCloser {
+	if w.encoding == "gzip" {
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(w.ResponseWriter)
+		return pooled{gz, func() { gzipWriters.Put(gz) }}
+	}
+	fl := flateWriters.Get().(*flate.Writer)
+	fl.Reset(w.ResponseWriter)
+	return pooled{fl, func() { flateWriters.Put(fl) }}
+}
+
+// Flush sends what was written so far, for streamed responses.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if f, ok := w.writer.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close writes the response if it was held back, and ends the compressed
+// stream.
+func (w *compressWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if w.writer != nil {
+		return w.writer.Close()
+	}
+	return nil
+}
+
+func compressible(contentType string) bool {
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// pooled returns its writer to the pool once closed.
+type pooled struct {
+	writer interface {
+		io.WriteCloser
+		Flush() error
+	}
+	release func()
+}
+
+func (p pooled) Write(b []byte) (int, error) { return p.writer.Write(b) }
+func (p pooled) Flush() error                { return p.writer.Flush() }
+
+func (p pooled) Close() error {
+	err := p.writer.Close()
+	p.release()
+	return err
+}
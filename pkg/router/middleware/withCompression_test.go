@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odas0r/zet/pkg/router/middleware"
+)
+
+func TestWithCompression(t *testing.T) {
+	long := strings.Repeat("zettel ", 500)
+
+	type testCase struct {
+		test             string
+		acceptEncoding   string
+		contentType      string
+		body             string
+		expectedEncoding string
+	}
+
+	testCases := []testCase{
+		{test: "gzip", acceptEncoding: "gzip, deflate", contentType: "text/html", body: long, expectedEncoding: "gzip"},
+		{test: "deflate", acceptEncoding: "deflate", contentType: "application/json", body: long, expectedEncoding: "deflate"},
+		{test: "gzip refused", acceptEncoding: "gzip;q=0, deflate", contentType: "text/html", body: long, expectedEncoding: "deflate"},
+		{test: "No encoding accepted", contentType: "text/html", body: long},
+		{test: "Small body", acceptEncoding: "gzip", contentType: "text/html", body: "zettel"},
+		{test: "Not text", acceptEncoding: "gzip", contentType: "image/png", body: long},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			handler := middleware.WithCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				// written in parts, as templ does
+				io.WriteString(w, tc.body[:len(tc.body)/2])
+				io.WriteString(w, tc.body[len(tc.body)/2:])
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			encoding := rec.Header().Get("Content-Encoding")
+			if encoding != tc.expectedEncoding {
+				t.Fatalf("expected encoding %q, got %q", tc.expectedEncoding, encoding)
+			}
+
+			var body io.Reader = rec.Body
+			switch encoding {
+			case "gzip":
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+			case "deflate":
+				body = flate.NewReader(rec.Body)
+			}
+			b, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tc.body {
+				t.Errorf("expected the body back, got %d bytes", len(b))
+			}
+		})
+	}
+}
+
+func TestWithCompression_NotModified(t *testing.T) {
+	handler := middleware.WithCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusNotModified || rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 0 {
+		t.Errorf("expected an empty 304, got %d %q with %d bytes", rec.Code, rec.Header().Get("Content-Encoding"), rec.Body.Len())
+	}
+}
+
+func TestWithCompression_Range(t *testing.T) {
+	long := strings.Repeat("zettel ", 500)
+	handler := middleware.WithCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "zettel.txt", time.Time{}, strings.NewReader(long))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	r.Header.Set("Range", "bytes=0-1499")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("expected status %d, got %d", http.StatusPartialContent, rec.Code)
+	}
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("expected the range as it is, got encoding %q", encoding)
+	}
+	if rec.Body.String() != long[:1500] {
+		t.Errorf("expected the first 1500 bytes, got %d bytes", rec.Body.Len())
+	}
+}
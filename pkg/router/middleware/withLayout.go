@@ -12,7 +12,13 @@ import (
 
 type ResponseWriter struct {
 	http.ResponseWriter
-	buf *bytes.Buffer
+	buf    *bytes.Buffer
+	status int
+}
+
+func (rw *ResponseWriter) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
 }
 
 func (rw *ResponseWriter) Write(b []byte) (int, error) {
@@ -34,6 +40,11 @@ func WithLayout(next http.Handler) http.Handler {
 
 		next.ServeHTTP(crw, r)
 
+		// the client still has the page, layout included
+		if crw.status == http.StatusNotModified {
+			return
+		}
+
 		html := crw.buf.String()
 
 		component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
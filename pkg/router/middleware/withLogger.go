@@ -1,11 +1,17 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/odas0r/zet/pkg/logger"
 )
 
+// RequestIDHeader carries the id of a request, sent back in the response.
+// A valid id set by a proxy in front of the server is kept.
+const RequestIDHeader = "X-Request-ID"
+
 // wrappedResponseWriter adds statusCode to ResponseWriter
 type wrappedResponseWriter struct {
 	http.ResponseWriter
@@ -18,15 +24,51 @@ func (w *wrappedResponseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 }
 
-// WithLogger logs each request with the response status and duration.
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WithLogger gives each request an id, carried by its context into the logs
+// of its queries, and logs the request with the response status, the
+// duration and the queries it ran.
 func WithLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = logger.NewRequestID()
+		}
+		ctx, request := logger.WithRequest(r.Context(), id)
+		w.Header().Set(RequestIDHeader, id)
+
 		wrapped := &wrappedResponseWriter{
 			ResponseWriter: w,
 			statusCode:     http.StatusOK, // Default status code
 		}
-		next.ServeHTTP(wrapped, r)
-		log.Printf("%s %s %d %s", r.Method, r.URL.Path, wrapped.statusCode, time.Since(start))
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+		queries, dbTime := request.Queries()
+		slog.InfoContext(ctx, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", wrapped.statusCode,
+			"duration", time.Since(start),
+			"queries", queries,
+			"db_time", dbTime,
+		)
 	})
 }
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/odas0r/zet/pkg/view"
+)
+
+// WithURLs puts the routes in the context of the request, for the views to
+// link to them by name.
+func WithURLs(urls view.URLs) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(view.WithURLs(r.Context(), urls)))
+		})
+	}
+}
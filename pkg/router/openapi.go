@@ -0,0 +1,245 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Doc is the schema metadata of a route. Bodies are given as values of
+// their Go type, whose JSON form becomes the schema.
+type Doc struct {
+	Summary string
+	Tags    []string
+	Query   []Param
+	// Request is the request body, nil for routes without one.
+	Request any
+	// Status is the status of a successful response, http.StatusOK by
+	// default.
+	Status int
+	// Response is the body of a successful response, nil for routes without
+	// one.
+	Response any
+	// Errors are the error statuses of the route, all answered with the
+	// Error body.
+	Errors []int
+	Error  any
+}
+
+// Param is a query parameter of a route.
+type Param struct {
+	Name        string
+	Description string
+	Type        string
+	Required    bool
+}
+
+// Info is the general information of the OpenAPI document.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI string                          `json:"openapi"`
+	Info    Info                            `json:"info"`
+	Paths   map[string]map[string]Operation `json:"paths"`
+}
+
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var pathParamRegex = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// OpenAPI builds the OpenAPI document of the routes that have a Doc.
+func (r *Router) OpenAPI(info Info) Document {
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]map[string]Operation{},
+	}
+
+	for _, route := range *r.routes {
+		if route.Doc == nil {
+			continue
+		}
+		path := strings.TrimSuffix(route.Path, "{$}")
+		path = pathParamRegex.ReplaceAllString(path, "{$1}")
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]Operation{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = newOperation(route)
+	}
+	return doc
+}
+
+// HandleOpenAPI serves the OpenAPI document of the router, built on each
+// request so that it includes the routes registered after it.
+func (r *Router) HandleOpenAPI(info Info) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(r.OpenAPI(info))
+	}
+}
+
+func newOperation(route *Route) Operation {
+	d := route.Doc
+	op := Operation{
+		Summary:   d.Summary,
+		Tags:      d.Tags,
+		Responses: map[string]Response{},
+	}
+
+	for _, match := range pathParamRegex.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, param := range d.Query {
+		kind := param.Type
+		if kind == "" {
+			kind = "string"
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: kind},
+		})
+	}
+
+	if d.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(d.Request),
+		}
+	}
+
+	status := d.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	if d.Response != nil {
+		success.Content = jsonContent(d.Response)
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	for _, status := range d.Errors {
+		response := Response{Description: http.StatusText(status)}
+		if d.Error != nil {
+			response.Content = jsonContent(d.Error)
+		}
+		op.Responses[strconv.Itoa(status)] = response
+	}
+	return op
+}
+
+func jsonContent(v any) map[string]MediaType {
+	return map[string]MediaType{
+		"application/json": {Schema: schemaOf(reflect.TypeOf(v))},
+	}
+}
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// schemaOf returns the schema of the JSON encoding of a Go type.
+func schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := schemaOf(t.Elem())
+		schema.Nullable = true
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Struct:
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			schema.Properties[name] = schemaOf(field.Type)
+		}
+		return schema
+	}
+	// interfaces hold any value
+	return &Schema{}
+}
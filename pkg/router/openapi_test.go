@@ -0,0 +1,114 @@
+package router_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/router"
+	"github.com/odas0r/zet/pkg/router/middleware"
+)
+
+type note struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Tags      []string  `json:"tags,omitempty"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	Parent    *note     `json:"-"`
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {}
+
+func TestRouter_Routes(t *testing.T) {
+	r := router.New()
+	api := r.Group("/api")
+	api.Use(middleware.WithLogger)
+	api.HandleFunc("GET /notes", handle, middleware.WithMethods("GET"))
+	r.HandleFunc("GET /", handle, middleware.WithDisableCache(false))
+
+	routes := r.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("expected the routes of the group to be listed, got %d routes", len(routes))
+	}
+	if routes[0].Path != "/api/notes" || routes[0].Pattern != "GET /api/notes" {
+		t.Errorf("expected the path to have the prefix of the group, got %q", routes[0].Pattern)
+	}
+	if want := []string{"WithLogger", "WithMethods"}; !slices.Equal(routes[0].Middleware, want) {
+		t.Errorf("expected middleware %v, got %v", want, routes[0].Middleware)
+	}
+	if len(routes[1].Middleware) != 0 {
+		t.Errorf("expected a disabled middleware to be left out, got %v", routes[1].Middleware)
+	}
+}
+
+func TestRouter_OpenAPI(t *testing.T) {
+	r := router.New()
+	r.HandleFunc("GET /notes/{id}", handle).Describe(router.Doc{
+		Summary:  "Get a note",
+		Response: note{},
+		Errors:   []int{http.StatusNotFound},
+	})
+	r.HandleFunc("POST /notes/{path...}", handle).Describe(router.Doc{
+		Request: note{},
+		Status:  http.StatusCreated,
+	})
+	r.HandleFunc("GET /hidden", handle)
+
+	doc := r.OpenAPI(router.Info{Title: "test", Version: "1"})
+
+	if len(doc.Paths) != 2 {
+		t.Fatalf("expected only the described routes, got %v", doc.Paths)
+	}
+
+	get, ok := doc.Paths["/notes/{id}"]["get"]
+	if !ok {
+		t.Fatalf("expected GET /notes/{id}, got %v", doc.Paths)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Name != "id" || get.Parameters[0].In != "path" {
+		t.Errorf("expected the id path parameter, got %+v", get.Parameters)
+	}
+	if _, ok := get.Responses["404"]; !ok {
+		t.Errorf("expected the error response, got %v", get.Responses)
+	}
+
+	schema := get.Responses["200"].Content["application/json"].Schema
+	type testCase struct {
+		test     string
+		property string
+		kind     string
+		format   string
+	}
+	for _, tc := range []testCase{
+		{test: "uuid", property: "id", kind: "string", format: "uuid"},
+		{test: "string", property: "title", kind: "string"},
+		{test: "slice", property: "tags", kind: "array"},
+		{test: "time", property: "created_at", kind: "string", format: "date-time"},
+	} {
+		t.Run(tc.test, func(t *testing.T) {
+			property, ok := schema.Properties[tc.property]
+			if !ok {
+				t.Fatalf("expected property %q, got %v", tc.property, schema.Properties)
+			}
+			if property.Type != tc.kind || property.Format != tc.format {
+				t.Errorf("expected %s %s, got %s %s", tc.kind, tc.format, property.Type, property.Format)
+			}
+		})
+	}
+	if _, ok := schema.Properties["Secret"]; ok {
+		t.Errorf("expected fields ignored by json to be left out")
+	}
+
+	post, ok := doc.Paths["/notes/{path}"]["post"]
+	if !ok {
+		t.Fatalf("expected the wildcard to become a path parameter, got %v", doc.Paths)
+	}
+	if post.RequestBody == nil {
+		t.Errorf("expected a request body")
+	}
+	if _, ok := post.Responses["201"]; !ok {
+		t.Errorf("expected the 201 response, got %v", post.Responses)
+	}
+}
@@ -1,73 +1,267 @@
 package router
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/odas0r/zet/pkg/router/middleware"
 )
 
+var (
+	ErrRouteNotFound = errors.New("error: no route has this name")
+	ErrRouteParams   = errors.New("error: the params do not match the wildcards of the route")
+)
+
 type Route struct {
 	Method  string
 	Pattern string
+	// Path is the pattern without the method.
+	Path string
+	// Name identifies the route to build its URL with Router.URL.
+	Name string
+	// Middleware holds the names of the middleware the route goes through,
+	// from the outermost one.
+	Middleware []string
+	// Doc describes the route in the OpenAPI document, which leaves out the
+	// routes without one.
+	Doc *Doc
+
+	names map[string]*Route
+}
+
+// Describe attaches the description of the route to the OpenAPI document.
+func (rt *Route) Describe(doc Doc) *Route {
+	rt.Doc = &doc
+	return rt
+}
+
+// Named names the route, for the views to build its URL. Like the patterns
+// of http.ServeMux, a name taken by another route panics.
+func (rt *Route) Named(name string) *Route {
+	if other, ok := rt.names[name]; ok {
+		panic(fmt.Sprintf("router: the name %q of %q is taken by %q", name, rt.Pattern, other.Pattern))
+	}
+	rt.Name = name
+	rt.names[name] = rt
+	return rt
 }
 
 type Router struct {
 	mux        *http.ServeMux
 	middleware []middleware.Middleware
 	prefix     string
-	routes     []Route
+	// routes, names and fallbacks are shared with the groups, so that every
+	// route is listed and found by name from any of them
+	routes    *[]*Route
+	names     map[string]*Route
+	fallbacks *[]*fallback
+}
+
+// fallback answers the requests under prefix that no route matches.
+type fallback struct {
+	prefix           string
+	notFound         http.Handler
+	methodNotAllowed http.Handler
 }
 
 func New() *Router {
 	return &Router{
 		mux:        http.NewServeMux(),
-		routes:     []Route{},
+		routes:     &[]*Route{},
+		names:      map[string]*Route{},
+		fallbacks:  &[]*fallback{},
 		middleware: []middleware.Middleware{},
 	}
 }
 
-func (r *Router) HandleFunc(pattern string, handler http.HandlerFunc, routeMiddleware ...middleware.Middleware) {
+func (r *Router) HandleFunc(pattern string, handler http.HandlerFunc, routeMiddleware ...middleware.Middleware) *Route {
+	return r.Handle(pattern, handler, routeMiddleware...)
+}
+
+func (r *Router) Handle(pattern string, handler http.Handler, routeMiddleware ...middleware.Middleware) *Route {
 	parts := strings.Split(pattern, " ")
 
 	method := parts[0]
-	pattern = parts[1]
+	path := r.prefix + parts[1]
+
+	pattern = fmt.Sprintf("%s %s", method, path)
+	route := &Route{Method: method, Pattern: pattern, Path: path, names: r.names}
+	r.mux.Handle(pattern, withRoute(route, r.applyMiddleware(handler, routeMiddleware...)))
 
-	pattern = fmt.Sprintf("%s %s%s", method, r.prefix, pattern)
-	h := r.applyMiddleware(http.HandlerFunc(handler), routeMiddleware...)
-	r.mux.HandleFunc(pattern, h.ServeHTTP)
-	r.routes = append(r.routes, Route{Method: method, Pattern: pattern})
+	for _, m := range append(slices.Clone(r.middleware), routeMiddleware...) {
+		if name := middleware.Name(m); name != "" {
+			route.Middleware = append(route.Middleware, name)
+		}
+	}
+	*r.routes = append(*r.routes, route)
+	return route
 }
 
-func (r *Router) Handle(pattern string, handler http.Handler, routeMiddleware ...middleware.Middleware) {
-	parts := strings.Split(pattern, " ")
+type routeKey struct{}
 
-	method := parts[0]
-	pattern = parts[1]
+// withRoute puts the route in the context of its requests, ahead of every
+// middleware.
+func withRoute(route *Route, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeKey{}, route)))
+	})
+}
 
-	pattern = fmt.Sprintf("%s %s%s", method, r.prefix, pattern)
-	r.mux.Handle(pattern, r.applyMiddleware(handler, routeMiddleware...))
-	r.routes = append(r.routes, Route{Method: method, Pattern: pattern})
+// CurrentRoute returns the route that matched the request.
+func CurrentRoute(r *http.Request) (*Route, bool) {
+	route, ok := r.Context().Value(routeKey{}).(*Route)
+	return route, ok
 }
 
+// Group returns a router for the routes under prefix, going through the
+// middleware of r first. The middleware used on the group afterwards is its
+// own, see Use.
 func (r *Router) Group(prefix string) *Router {
 	return &Router{
 		mux:        r.mux,
 		middleware: r.middleware,
 		prefix:     r.prefix + strings.TrimSuffix(prefix, "/"),
 		routes:     r.routes,
+		names:      r.names,
+		fallbacks:  r.fallbacks,
+	}
+}
+
+// NotFound answers, through the middleware of the router, the requests under
+// its prefix whose path no route matches, instead of the plain 404 of
+// http.ServeMux. The router with the longest prefix answers.
+func (r *Router) NotFound(handler http.HandlerFunc) {
+	r.fallback().notFound = r.applyMiddleware(handler)
+}
+
+// MethodNotAllowed answers, through the middleware of the router, the
+// requests under its prefix whose path is matched by routes of other
+// methods only. The Allow header lists those methods.
+func (r *Router) MethodNotAllowed(handler http.HandlerFunc) {
+	r.fallback().methodNotAllowed = r.applyMiddleware(handler)
+}
+
+func (r *Router) fallback() *fallback {
+	for _, f := range *r.fallbacks {
+		if f.prefix == r.prefix {
+			return f
+		}
+	}
+	f := &fallback{prefix: r.prefix}
+	*r.fallbacks = append(*r.fallbacks, f)
+	return f
+}
+
+// URL returns the path of the named route, with the params in place of the
+// wildcards of its pattern, in order.
+func (r *Router) URL(name string, params ...any) (string, error) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrRouteNotFound, name)
+	}
+
+	segments := strings.Split(route.Path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		// {$} only anchors the end of the path
+		if segment == "{$}" {
+			segments[i] = ""
+			continue
+		}
+		if len(params) == 0 {
+			return "", fmt.Errorf("%w: %q takes more params than given", ErrRouteParams, route.Path)
+		}
+		value := fmt.Sprint(params[0])
+		params = params[1:]
+		if strings.HasSuffix(segment, "...}") {
+			// the rest of the path keeps its slashes
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+			continue
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	if len(params) > 0 {
+		return "", fmt.Errorf("%w: %q takes fewer params than given", ErrRouteParams, route.Path)
 	}
+	return strings.Join(segments, "/"), nil
 }
 
+// Routes returns the routes registered on the router and its groups, in the
+// order they were registered.
+func (r *Router) Routes() []*Route {
+	return *r.routes
+}
+
+// Use adds middleware to the routes registered afterwards. The stack is
+// copied on write, so that it never reaches the parent of a group or its
+// other groups, which share the same array.
 func (r *Router) Use(middleware ...middleware.Middleware) {
-	r.middleware = append(r.middleware, middleware...)
+	r.middleware = append(slices.Clip(r.middleware), middleware...)
 }
 
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler, pattern := r.mux.Handler(req); pattern == "" {
+		// the mux answers the requests no route matches with 404 or 405,
+		// and the methods allowed, which tells which fallback to use
+		unmatched := &unmatchedWriter{header: http.Header{}}
+		handler.ServeHTTP(unmatched, req)
+		if fallback := r.fallbackFor(req.URL.Path, unmatched.status); fallback != nil {
+			if allow := unmatched.header.Get("Allow"); allow != "" {
+				w.Header().Set("Allow", allow)
+			}
+			fallback.ServeHTTP(w, req)
+			return
+		}
+	}
 	r.mux.ServeHTTP(w, req)
 }
 
+// fallbackFor returns the handler of the fallback with the longest prefix of
+// the path for the status, or nil when there is none.
+func (r *Router) fallbackFor(path string, status int) http.Handler {
+	var handler http.Handler
+	longest := -1
+	for _, f := range *r.fallbacks {
+		if path != f.prefix && !strings.HasPrefix(path, f.prefix+"/") || len(f.prefix) <= longest {
+			continue
+		}
+		h := f.notFound
+		if status == http.StatusMethodNotAllowed {
+			h = f.methodNotAllowed
+		} else if status != http.StatusNotFound {
+			h = nil
+		}
+		if h != nil {
+			handler, longest = h, len(f.prefix)
+		}
+	}
+	return handler
+}
+
+// unmatchedWriter keeps the status and headers of the answer of the mux to
+// an unmatched request, and discards its body.
+type unmatchedWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *unmatchedWriter) Header() http.Header         { return w.header }
+func (w *unmatchedWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *unmatchedWriter) WriteHeader(status int)      { w.status = status }
+
 func (r *Router) applyMiddleware(handler http.Handler, routeMiddleware ...middleware.Middleware) http.Handler {
 	// Apply route-specific middleware first
 	for i := len(routeMiddleware) - 1; i >= 0; i-- {
@@ -80,8 +274,12 @@ func (r *Router) applyMiddleware(handler http.Handler, routeMiddleware ...middle
 	return handler
 }
 
-func (r *Router) PrintRoutes() {
-	for _, route := range r.routes {
-		println(route.Pattern)
+// PrintRoutes writes a line per route with its method, path, name and
+// middleware.
+func (r *Router) PrintRoutes(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, route := range *r.routes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Name, strings.Join(route.Middleware, ", "))
 	}
+	tw.Flush()
 }
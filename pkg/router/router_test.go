@@ -0,0 +1,169 @@
+package router_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/router"
+	"github.com/odas0r/zet/pkg/router/middleware"
+)
+
+// header returns a middleware that adds its name to the X-Middleware header.
+func header(name string) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middleware", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func serve(r *router.Router, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestRouter_Group(t *testing.T) {
+	r := router.New()
+	r.Use(header("root"))
+	// a spare capacity in the stack of the parent used to be shared by the
+	// groups, so that the middleware of one replaced the other's
+	r.Use(header("spare"))
+	r.Use(header("spare"))
+
+	a := r.Group("/a")
+	b := r.Group("/b")
+	a.Use(header("a"))
+	b.Use(header("b"))
+	a.HandleFunc("GET /", handle)
+	b.HandleFunc("GET /", handle)
+	r.Use(header("late"))
+	r.HandleFunc("GET /", handle)
+
+	type testCase struct {
+		path     string
+		expected []string
+	}
+
+	testCases := []testCase{
+		{path: "/a/", expected: []string{"root", "spare", "spare", "a"}},
+		{path: "/b/", expected: []string{"root", "spare", "spare", "b"}},
+		{path: "/", expected: []string{"root", "spare", "spare", "late"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			got := serve(r, http.MethodGet, tc.path).Header().Values("X-Middleware")
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected middleware %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Fatalf("expected middleware %v, got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRouter_Fallbacks(t *testing.T) {
+	r := router.New()
+	r.HandleFunc("GET /notes", handle)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "page", http.StatusNotFound)
+	})
+	api := r.Group("/api")
+	api.Use(header("api"))
+	api.HandleFunc("GET /notes", handle)
+	api.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "api", http.StatusNotFound)
+	})
+	api.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "api method", http.StatusMethodNotAllowed)
+	})
+
+	type testCase struct {
+		test           string
+		method, path   string
+		expectedStatus int
+		expectedBody   string
+	}
+
+	testCases := []testCase{
+		{test: "Matched", method: http.MethodGet, path: "/notes", expectedStatus: http.StatusOK},
+		{test: "Not found", method: http.MethodGet, path: "/missing", expectedStatus: http.StatusNotFound, expectedBody: "page\n"},
+		{test: "Not found in a group", method: http.MethodGet, path: "/api/missing", expectedStatus: http.StatusNotFound, expectedBody: "api\n"},
+		{test: "Not found by a prefix", method: http.MethodGet, path: "/apis", expectedStatus: http.StatusNotFound, expectedBody: "page\n"},
+		{test: "Method not allowed in a group", method: http.MethodPost, path: "/api/notes", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "api method\n"},
+		// without a handler, the mux answers
+		{test: "Method not allowed", method: http.MethodPost, path: "/notes", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "Method Not Allowed\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			rec := serve(r, tc.method, tc.path)
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, rec.Code)
+			}
+			if tc.expectedBody != "" && rec.Body.String() != tc.expectedBody {
+				t.Errorf("expected body %q, got %q", tc.expectedBody, rec.Body.String())
+			}
+			if tc.expectedStatus == http.StatusMethodNotAllowed && rec.Header().Get("Allow") == "" {
+				t.Error("expected the allowed methods")
+			}
+		})
+	}
+
+	if got := serve(r, http.MethodGet, "/api/missing").Header().Get("X-Middleware"); got != "api" {
+		t.Errorf("expected the fallback to go through the middleware of its group, got %q", got)
+	}
+}
+
+func TestRouter_URL(t *testing.T) {
+	r := router.New()
+	r.HandleFunc("GET /{$}", handle).Named("home")
+	notes := r.Group("/notes")
+	notes.HandleFunc("GET /{id}/links/{linkId}", handle).Named("link")
+	r.HandleFunc("GET /files/{path...}", handle).Named("file")
+
+	type testCase struct {
+		test        string
+		name        string
+		params      []any
+		expected    string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{test: "Without wildcards", name: "home", expected: "/"},
+		{test: "With wildcards in a group", name: "link", params: []any{1, "a b"}, expected: "/notes/1/links/a%20b"},
+		{test: "With the rest of the path", name: "file", params: []any{"a/b c"}, expected: "/files/a/b%20c"},
+		{test: "Unknown name", name: "missing", expectedErr: router.ErrRouteNotFound},
+		{test: "Missing params", name: "link", params: []any{1}, expectedErr: router.ErrRouteParams},
+		{test: "Extra params", name: "home", params: []any{1}, expectedErr: router.ErrRouteParams},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			got, err := r.URL(tc.name, tc.params...)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("Taken name", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a taken name to panic")
+			}
+		}()
+		r.HandleFunc("GET /other", handle).Named("home")
+	})
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	ErrSocketTLS   = errors.New("error: TLS is not served over a Unix socket")
+	ErrSocketInUse = errors.New("error: another server is listening on the socket")
+	ErrTLSKeyPair  = errors.New("error: TLS needs both a certificate and its key")
+)
+
+// Config is the configuration of the HTTP server. The timeouts keep slow or
+// idle clients from holding connections forever.
+type Config struct {
+	Address string
+	Port    string
+	// Socket is the path of a Unix domain socket to listen on instead of
+	// the address and port.
+	Socket string
+	// TLSCert and TLSKey are the files of the certificate served over
+	// HTTPS. With SelfSigned, a certificate is generated on start instead.
+	TLSCert    string
+	TLSKey     string
+	SelfSigned bool
+	// ReadHeaderTimeout is the time to read the headers of a request, and
+	// ReadTimeout the time to read all of it.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	// WriteTimeout is the time from the end of the request headers to the
+	// end of the response.
+	WriteTimeout time.Duration
+	// IdleTimeout is how long a kept-alive connection waits for the next
+	// request.
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	// ShutdownTimeout is how long the requests in flight have to finish
+	// once the server stops.
+	ShutdownTimeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Address:           "localhost",
+		Port:              "3000",
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+		ShutdownTimeout:   10 * time.Second,
+	}
+}
+
+// Server serves a handler with its configuration.
+type Server struct {
+	http   *http.Server
+	config Config
+}
+
+func New(config Config, handler http.Handler) *Server {
+	return &Server{
+		config: config,
+		http: &http.Server{
+			Addr:              net.JoinHostPort(config.Address, config.Port),
+			Handler:           handler,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			ReadTimeout:       config.ReadTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+			MaxHeaderBytes:    config.MaxHeaderBytes,
+		},
+	}
+}
+
+// Run serves until the context is done, then stops accepting connections
+// and waits for the requests in flight, for up to the shutdown timeout.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.http.TLSConfig != nil {
+			// the certificate is in the TLS config
+			serveErr <- s.http.ServeTLS(listener, "", "")
+			return
+		}
+		serveErr <- s.http.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, waiting for the requests in flight")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// listen opens the Unix socket or the TCP address, and sets up TLS when the
+// server has a certificate.
+func (s *Server) listen() (net.Listener, error) {
+	if s.config.Socket != "" {
+		if s.config.TLSCert != "" || s.config.SelfSigned {
+			return nil, ErrSocketTLS
+		}
+		// a socket left behind by a server that did not stop cleanly
+		// would make the address already in use
+		if info, err := os.Stat(s.config.Socket); err == nil && info.Mode()&fs.ModeSocket != 0 {
+			if conn, err := net.Dial("unix", s.config.Socket); err == nil {
+				conn.Close()
+				return nil, ErrSocketInUse
+			}
+			if err := os.Remove(s.config.Socket); err != nil {
+				return nil, err
+			}
+		}
+		listener, err := net.Listen("unix", s.config.Socket)
+		if err != nil {
+			return nil, err
+		}
+		// only the user running the server may connect to it
+		if err := os.Chmod(s.config.Socket, 0o600); err != nil {
+			listener.Close()
+			return nil, err
+		}
+		slog.Info("listening", "socket", s.config.Socket)
+		return listener, nil
+	}
+
+	config, err := s.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		slog.Info("listening", "url", "http://"+listener.Addr().String())
+		return listener, nil
+	}
+	s.http.TLSConfig = config
+	slog.Info("listening", "url", "https://"+listener.Addr().String())
+	return listener, nil
+}
@@ -0,0 +1,164 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odas0r/zet/pkg/server"
+)
+
+// socketClient returns a client that connects to the server on the socket.
+func socketClient(socket string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+}
+
+// get requests the server as soon as it listens on the socket.
+func get(client *http.Client) (*http.Response, error) {
+	var res *http.Response
+	var err error
+	for range 20 {
+		if res, err = client.Get("http://zet/"); err == nil {
+			return res, nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil, err
+}
+
+func TestServer_Run(t *testing.T) {
+	config := server.DefaultConfig()
+	config.Port = "0"
+
+	// the server stops even when the context is done before it serves
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.New(config, http.NotFoundHandler()).Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(config.ShutdownTimeout):
+		t.Fatal("expected the server to stop when the context is done")
+	}
+}
+
+func TestServer_RunDrains(t *testing.T) {
+	config := server.DefaultConfig()
+	config.Socket = filepath.Join(t.TempDir(), "zet.sock")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		finished.Store(true)
+		io.WriteString(w, "done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.New(config, handler).Run(ctx)
+	}()
+
+	type response struct {
+		res *http.Response
+		err error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		res, err := get(socketClient(config.Socket))
+		responses <- response{res, err}
+	}()
+
+	select {
+	case <-started:
+	case r := <-responses:
+		t.Fatalf("expected the request to reach the handler, got %v", r.err)
+	}
+	cancel()
+	// the server stops listening at once, while the request is still in
+	// flight
+	listening := true
+	for i := 0; i < 20 && listening; i++ {
+		conn, err := net.Dial("unix", config.Socket)
+		if listening = err == nil; listening {
+			conn.Close()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if listening {
+		t.Error("expected the server to stop listening")
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("expected the server to wait for the request in flight, got %v", err)
+	default:
+	}
+	close(release)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a clean shutdown, got %v", err)
+		}
+		if !finished.Load() {
+			t.Error("expected the request in flight to finish before the server stopped")
+		}
+	case <-time.After(config.ShutdownTimeout):
+		t.Fatal("expected the server to stop once the request finished")
+	}
+
+	r := <-responses
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	defer r.res.Body.Close()
+	body, err := io.ReadAll(r.res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.res.StatusCode != http.StatusOK || string(body) != "done" {
+		t.Errorf("expected the whole response, got %d %q", r.res.StatusCode, body)
+	}
+}
+
+func TestServer_RunSocket(t *testing.T) {
+	config := server.DefaultConfig()
+	config.Socket = filepath.Join(t.TempDir(), "zet.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.New(config, http.NotFoundHandler()).Run(ctx)
+
+	res, err := get(socketClient(config.Socket))
+	if err != nil {
+		t.Fatalf("expected the server to answer on the socket, got %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, res.StatusCode)
+	}
+
+	config.TLSCert = "cert.pem"
+	if err := server.New(config, http.NotFoundHandler()).Run(ctx); err != server.ErrSocketTLS {
+		t.Errorf("expected error %v, got %v", server.ErrSocketTLS, err)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// CheckAliases ensures no other zettel in the workspaces of z, or in the
+// given ones, is known by one of the aliases of z, nor has the title of z as
+// an alias.
+func (s *Service) CheckAliases(ctx context.Context, z zettel.Zettel, workspaceIDs ...uuid.UUID) error {
+	workspaces, err := s.workspaceRepo.FindWorkspacesByZettelID(ctx, z.ID())
+	if err != nil {
+		return err
+	}
+	for _, wrk := range workspaces {
+		workspaceIDs = append(workspaceIDs, wrk.ID())
+	}
+
+	for _, workspaceID := range workspaceIDs {
+		for _, alias := range z.Aliases() {
+			other, err := s.zettelRepo.FindByNameInWorkspace(ctx, workspaceID, alias)
+			if err == zettel.ErrZettelNotFound {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if other.ID() != z.ID() {
+				return fmt.Errorf("%w: %q names %q", zettel.ErrAliasAlreadyExists, alias, other.Title())
+			}
+		}
+
+		other, err := s.zettelRepo.FindByAliasInWorkspace(ctx, workspaceID, z.Title())
+		if err == zettel.ErrZettelNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if other.ID() != z.ID() {
+			return fmt.Errorf("%w: %q names %q", zettel.ErrAliasAlreadyExists, z.Title(), other.Title())
+		}
+	}
+	return nil
+}
+
+// FindByName returns the zettels known by the query: the zettel with it as
+// title or alias or, when there is none, the zettels whose names contain it.
+func (s *Service) FindByName(ctx context.Context, query string) ([]zettel.Zettel, error) {
+	z, err := s.zettelRepo.FindByTitle(ctx, query)
+	if err == nil {
+		return []zettel.Zettel{z}, nil
+	} else if err != zettel.ErrZettelNotFound {
+		return nil, err
+	}
+	return s.zettelRepo.SearchByName(ctx, query)
+}
+
+// Resolve returns the id of the zettel known by name, preferring the zettels
+// of the workspace.
+func (s *Service) Resolve(ctx context.Context, workspaceID uuid.UUID, name string) (uuid.UUID, bool) {
+	if z, err := s.zettelRepo.FindByNameInWorkspace(ctx, workspaceID, name); err == nil {
+		return z.ID(), true
+	}
+	if z, err := s.zettelRepo.FindByTitle(ctx, name); err == nil {
+		return z.ID(), true
+	}
+	return uuid.Nil, false
+}
@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestService_CheckAliases(t *testing.T) {
+	wrk := createWorkspace(t)
+	other := createWorkspace(t)
+
+	alias := uuid.NewString()
+	aliased, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	aliased.AddAlias(alias)
+	if err := svc.CreateZettel(ctx, aliased, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		test        string
+		zettel      func() zettel.Zettel
+		workspaceID uuid.UUID
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			test: "Alias of another zettel",
+			zettel: func() zettel.Zettel {
+				z, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+				z.AddAlias(alias)
+				return z
+			},
+			workspaceID: wrk.ID(),
+			expectedErr: zettel.ErrAliasAlreadyExists,
+		},
+		{
+			test: "Alias of another zettel in another case",
+			zettel: func() zettel.Zettel {
+				z, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+				z.AddAlias(strings.ToUpper(alias))
+				return z
+			},
+			workspaceID: wrk.ID(),
+			expectedErr: zettel.ErrAliasAlreadyExists,
+		},
+		{
+			test: "Title that is the alias of another zettel",
+			zettel: func() zettel.Zettel {
+				z, _ := zettel.New(alias, "content", zettel.Fleet)
+				return z
+			},
+			workspaceID: wrk.ID(),
+			expectedErr: zettel.ErrAliasAlreadyExists,
+		},
+		{
+			test: "Alias of a zettel of another workspace",
+			zettel: func() zettel.Zettel {
+				z, _ := zettel.New(alias, "content", zettel.Fleet)
+				z.AddAlias(alias + " 2")
+				return z
+			},
+			workspaceID: other.ID(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			if err := svc.CheckAliases(ctx, tc.zettel(), tc.workspaceID); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestService_ResolveAlias(t *testing.T) {
+	wrk := createWorkspace(t)
+
+	alias := uuid.NewString()
+	aliased, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	aliased.AddAlias(strings.ToUpper(alias))
+	if err := svc.CreateZettel(ctx, aliased, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the aliases are found in any case, as they are kept unique
+	for _, name := range []string{alias, strings.ToUpper(alias)} {
+		if id, ok := svc.Resolve(ctx, wrk.ID(), name); !ok || id != aliased.ID() {
+			t.Errorf("expected %q to resolve to %s, got %s", name, aliased.ID(), id)
+		}
+		if z, err := zettelRepo.FindByTitle(ctx, name); err != nil || z.ID() != aliased.ID() {
+			t.Errorf("expected %q to find %s, got %v", name, aliased.ID(), err)
+		}
+	}
+}
+
+// The database keeps the aliases unique in a workspace, whatever the way
+// the zettels are saved.
+func TestService_UniqueAliases(t *testing.T) {
+	wrk := createWorkspace(t)
+
+	alias := uuid.NewString()
+	aliased, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	aliased.AddAlias(alias)
+	z, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	for _, created := range []zettel.Zettel{aliased, z} {
+		if err := svc.CreateZettel(ctx, created, wrk.ID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Alias saved", func(t *testing.T) {
+		z.AddAlias(alias)
+		if err := zettelRepo.Save(ctx, z); !errors.Is(err, zettel.ErrAliasAlreadyExists) {
+			t.Errorf("expected error %v, got %v", zettel.ErrAliasAlreadyExists, err)
+		}
+	})
+
+	t.Run("Zettel added to the workspace", func(t *testing.T) {
+		// in different case, as the names are compared without it
+		other, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+		other.AddAlias(strings.ToUpper(alias))
+		if err := zettelRepo.Save(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+		wrk, err := workspaceRepo.FindWorkspaceByID(ctx, wrk.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := wrk.AddZettel(other.ID()); err != nil {
+			t.Fatal(err)
+		}
+		if err := workspaceRepo.Save(ctx, wrk); !errors.Is(err, zettel.ErrAliasAlreadyExists) {
+			t.Errorf("expected error %v, got %v", zettel.ErrAliasAlreadyExists, err)
+		}
+	})
+}
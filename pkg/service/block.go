@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// BlockBacklink is a zettel with a link to a block of another zettel.
+type BlockBacklink struct {
+	Zettel  zettel.Zettel
+	BlockID string
+}
+
+// BlockBacklinks returns the links from other zettels to the existing blocks
+// of z, resolving their references the same way the links of the workspace
+// are.
+func (s *Service) BlockBacklinks(ctx context.Context, z zettel.Zettel, workspaceID uuid.UUID) ([]BlockBacklink, error) {
+	linking, err := s.zettelRepo.FindLinking(ctx, z.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	var backlinks []BlockBacklink
+	for _, from := range linking {
+		seen := map[string]bool{}
+		for _, ref := range from.References() {
+			blockID := ref.BlockID()
+			if blockID == "" || seen[blockID] {
+				continue
+			}
+			if _, ok := z.Block(blockID); !ok {
+				continue
+			}
+			if id, ok := s.Resolve(ctx, workspaceID, ref.Title); ok && id == z.ID() {
+				seen[blockID] = true
+				backlinks = append(backlinks, BlockBacklink{Zettel: from, BlockID: blockID})
+			}
+		}
+	}
+	return backlinks, nil
+}
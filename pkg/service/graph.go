@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/graph"
+)
+
+// Graph builds the graph of every zettel and link.
+func (s *Service) Graph(ctx context.Context) (*graph.Graph, error) {
+	zettels, err := s.zettelRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := s.workspaceRepo.FindAllWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := map[uuid.UUID][]uuid.UUID{}
+	for _, wrk := range workspaces {
+		for _, id := range wrk.ListZettelIDs() {
+			membership[id] = append(membership[id], wrk.ID())
+		}
+	}
+
+	return graph.New(zettels, membership), nil
+}
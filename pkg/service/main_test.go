@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/database"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	wq "github.com/odas0r/zet/pkg/domain/workspace/sqlite"
+	zq "github.com/odas0r/zet/pkg/domain/zettel/sqlite"
+	"github.com/odas0r/zet/pkg/service"
+)
+
+var (
+	svc           *service.Service
+	zettelRepo    *zq.SQLiteRepository
+	workspaceRepo *wq.SQLiteRepository
+	ctx           = context.Background()
+)
+
+func TestMain(m *testing.M) {
+	db := database.New(database.Options{
+		URL:                "../../zettel.db",
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	})
+
+	var err error
+	if zettelRepo, err = zq.New(db); err != nil {
+		log.Fatalf("Failed to set up zettel repository: %v", err)
+	}
+	if workspaceRepo, err = wq.New(db); err != nil {
+		log.Fatalf("Failed to set up workspace repository: %v", err)
+	}
+	svc = service.New(workspaceRepo, zettelRepo)
+
+	// Run the tests
+	m.Run()
+}
+
+// createWorkspace saves a new workspace, for the zettels of a test.
+func createWorkspace(t *testing.T) workspace.Workspace {
+	wrk, err := workspace.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workspaceRepo.Save(ctx, wrk); err != nil {
+		t.Fatal(err)
+	}
+	return wrk
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// Merge absorbs the zettel absorbID into keepID and deletes it. The content
+// of both zettels is concatenated and handed to combine, when given, which
+// returns the content the merged zettel should have. Links, workspace
+// memberships and the absorbed title, kept as an alias, all move to keepID.
+func (s *Service) Merge(ctx context.Context, keepID, absorbID uuid.UUID, combine func(content string) (string, error)) (zettel.Zettel, error) {
+	keep, err := s.zettelRepo.FindByID(ctx, keepID)
+	if err != nil {
+		return zettel.Zettel{}, err
+	}
+	absorb, err := s.zettelRepo.FindByID(ctx, absorbID)
+	if err != nil {
+		return zettel.Zettel{}, err
+	}
+
+	if err := keep.Absorb(absorb); err != nil {
+		return zettel.Zettel{}, err
+	}
+
+	if combine != nil {
+		content, err := combine(keep.Content())
+		if err != nil {
+			return zettel.Zettel{}, err
+		}
+		if content == "" {
+			return zettel.Zettel{}, zettel.ErrMissingValues
+		}
+		keep.SetBody(content)
+	}
+
+	if err := s.zettelRepo.Merge(ctx, keep, absorbID); err != nil {
+		return zettel.Zettel{}, err
+	}
+
+	return keep, nil
+}
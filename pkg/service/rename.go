@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/fs"
+)
+
+var (
+	ErrFileAlreadyExists = shared.Conflict("error: a file with the new title already exists")
+)
+
+// FileMove is a backing file that is renamed along with its zettel.
+type FileMove struct {
+	From string
+	To   string
+}
+
+// Rename describes the changes needed to give a zettel a new title.
+type Rename struct {
+	Zettel   zettel.Zettel
+	OldTitle string
+	NewTitle string
+	// Affected holds the zettels, other than Zettel, whose references to
+	// OldTitle are rewritten.
+	Affected []zettel.Zettel
+	Files    []FileMove
+}
+
+// PlanRename computes the changes needed to rename the zettel without
+// applying them, so they can be previewed. Passing the current title plans a
+// rename that only lists the referencing zettels.
+func (s *Service) PlanRename(ctx context.Context, id uuid.UUID, title string) (Rename, error) {
+	if title == "" {
+		return Rename{}, zettel.ErrMissingValues
+	}
+
+	z, err := s.zettelRepo.FindByID(ctx, id)
+	if err != nil {
+		return Rename{}, err
+	}
+
+	existing, err := s.zettelRepo.FindByTitle(ctx, title)
+	if err == nil && existing.ID() != id {
+		return Rename{}, zettel.ErrTitleAlreadyExists
+	} else if err != nil && err != zettel.ErrZettelNotFound {
+		return Rename{}, err
+	}
+
+	referencing, err := s.zettelRepo.FindReferencing(ctx, z.Title())
+	if err != nil {
+		return Rename{}, err
+	}
+
+	plan := Rename{
+		Zettel:   z,
+		OldTitle: z.Title(),
+		NewTitle: title,
+	}
+	for _, ref := range referencing {
+		if ref.ID() != id {
+			plan.Affected = append(plan.Affected, ref)
+		}
+	}
+
+	workspaces, err := s.workspaceRepo.FindWorkspacesByZettelID(ctx, id)
+	if err != nil {
+		return Rename{}, err
+	}
+	for _, wrk := range workspaces {
+		from := filepath.Join(wrk.Path(), zettel.FileName(plan.OldTitle))
+		to := filepath.Join(wrk.Path(), zettel.FileName(plan.NewTitle))
+		if from == to || !fs.Exists(from) {
+			continue
+		}
+		if fs.Exists(to) {
+			return Rename{}, ErrFileAlreadyExists
+		}
+		plan.Files = append(plan.Files, FileMove{From: from, To: to})
+	}
+
+	return plan, nil
+}
+
+// Rename applies a planned rename: the zettel gets its new title, every
+// reference to the old title is rewritten and the backing files are renamed.
+// The zettels are saved in one transaction and the files are moved back if
+// saving fails.
+func (s *Service) Rename(ctx context.Context, plan Rename) error {
+	z := plan.Zettel
+	if err := z.Rename(plan.NewTitle); err != nil {
+		return err
+	}
+	z.ReplaceReferences(plan.OldTitle, plan.NewTitle)
+
+	zettels := []zettel.Zettel{z}
+	for _, ref := range plan.Affected {
+		ref.ReplaceReferences(plan.OldTitle, plan.NewTitle)
+		zettels = append(zettels, ref)
+	}
+
+	var moved []FileMove
+	for _, f := range plan.Files {
+		if err := os.Rename(f.From, f.To); err != nil {
+			undoMoves(moved)
+			return err
+		}
+		moved = append(moved, f)
+	}
+
+	if err := s.zettelRepo.SaveAll(ctx, zettels); err != nil {
+		undoMoves(moved)
+		return err
+	}
+
+	return nil
+}
+
+// FilePath returns the path of the file backing the zettel in the first of
+// its workspaces that has one, or an empty string.
+func (s *Service) FilePath(ctx context.Context, z zettel.Zettel) (string, error) {
+	workspaces, err := s.workspaceRepo.FindWorkspacesByZettelID(ctx, z.ID())
+	if err != nil {
+		return "", err
+	}
+	for _, wrk := range workspaces {
+		path := filepath.Join(wrk.Path(), z.FileName())
+		if fs.Exists(path) {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+func undoMoves(moves []FileMove) {
+	for i := len(moves) - 1; i >= 0; i-- {
+		os.Rename(moves[i].To, moves[i].From)
+	}
+}
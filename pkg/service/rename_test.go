@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+	"github.com/odas0r/zet/pkg/fs"
+	"github.com/odas0r/zet/pkg/service"
+)
+
+// createZettelFile creates a zettel in the workspace along with its file.
+func createZettelFile(t *testing.T, wrk workspace.Workspace, content string) zettel.Zettel {
+	t.Helper()
+	z, _ := zettel.New(uuid.NewString(), content, zettel.Fleet)
+	if err := svc.CreateZettel(ctx, z, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wrk.Path(), z.FileName()), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return z
+}
+
+func TestService_Rename(t *testing.T) {
+	wrk := createWorkspace(t)
+	other := createWorkspace(t)
+
+	z := createZettelFile(t, wrk, "content")
+	oldTitle := z.Title()
+	// a zettel of another workspace refers to it too
+	referencing := createZettelFile(t, other, "see [["+oldTitle+"#Heading|this]]")
+
+	newTitle := uuid.NewString()
+	plan, err := svc.PlanRename(ctx, z.ID(), newTitle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Affected) != 1 || plan.Affected[0].ID() != referencing.ID() {
+		t.Fatalf("expected %s to be affected, got %v", referencing.ID(), plan.Affected)
+	}
+	if err := svc.Rename(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := zettelRepo.FindByID(ctx, z.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Title() != newTitle {
+		t.Errorf("expected title %q, got %q", newTitle, renamed.Title())
+	}
+	rewritten, err := zettelRepo.FindByID(ctx, referencing.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "see [[" + newTitle + "#Heading|this]]"; rewritten.Content() != expected {
+		t.Errorf("expected content %q, got %q", expected, rewritten.Content())
+	}
+	if fs.Exists(filepath.Join(wrk.Path(), zettel.FileName(oldTitle))) || !fs.Exists(filepath.Join(wrk.Path(), zettel.FileName(newTitle))) {
+		t.Error("expected the file to be moved to the new title")
+	}
+}
+
+func TestService_PlanRenameConflicts(t *testing.T) {
+	wrk := createWorkspace(t)
+	z := createZettelFile(t, wrk, "content")
+	taken := createZettelFile(t, wrk, "content")
+
+	// a file without a zettel
+	free := uuid.NewString()
+	if err := os.WriteFile(filepath.Join(wrk.Path(), zettel.FileName(free)), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		test        string
+		title       string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{test: "Title of another zettel", title: taken.Title(), expectedErr: zettel.ErrTitleAlreadyExists},
+		{test: "File of the new title", title: free, expectedErr: service.ErrFileAlreadyExists},
+		{test: "Empty title", title: "", expectedErr: zettel.ErrMissingValues},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			if _, err := svc.PlanRename(ctx, z.ID(), tc.title); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestService_RenameRollsBack(t *testing.T) {
+	type testCase struct {
+		test string
+		// fail makes the rename fail after the file of the zettel is moved
+		fail func(plan *service.Rename) context.Context
+	}
+
+	testCases := []testCase{
+		{
+			test: "Failed move",
+			fail: func(plan *service.Rename) context.Context {
+				dir := filepath.Dir(plan.Files[0].From)
+				plan.Files = append(plan.Files, service.FileMove{From: filepath.Join(dir, "missing.md"), To: filepath.Join(dir, "moved.md")})
+				return ctx
+			},
+		},
+		{
+			test: "Failed save",
+			fail: func(plan *service.Rename) context.Context {
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				return ctx
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			wrk := createWorkspace(t)
+			z := createZettelFile(t, wrk, "content")
+			referencing := createZettelFile(t, wrk, "see [["+z.Title()+"]]")
+
+			plan, err := svc.PlanRename(ctx, z.ID(), uuid.NewString())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := svc.Rename(tc.fail(&plan), plan); err == nil {
+				t.Fatal("expected the rename to fail")
+			}
+
+			if !fs.Exists(filepath.Join(wrk.Path(), z.FileName())) || fs.Exists(filepath.Join(wrk.Path(), zettel.FileName(plan.NewTitle))) {
+				t.Error("expected the file to be moved back")
+			}
+			saved, err := zettelRepo.FindByID(ctx, z.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Title() != z.Title() {
+				t.Errorf("expected title %q, got %q", z.Title(), saved.Title())
+			}
+			unchanged, err := zettelRepo.FindByID(ctx, referencing.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if unchanged.Content() != referencing.Content() {
+				t.Errorf("expected content %q, got %q", referencing.Content(), unchanged.Content())
+			}
+		})
+	}
+}
+
+func TestService_SaveRenamedZettel(t *testing.T) {
+	wrk := createWorkspace(t)
+	z := createZettelFile(t, wrk, "content")
+
+	plan, err := svc.PlanRename(ctx, z.ID(), uuid.NewString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the rename fails once the file of the zettel is moved
+	dir := filepath.Dir(plan.Files[0].From)
+	plan.Files = append(plan.Files, service.FileMove{From: filepath.Join(dir, "missing.md"), To: filepath.Join(dir, "moved.md")})
+
+	z.SetBody("edited")
+	if err := svc.SaveRenamedZettel(ctx, z, wrk.ID(), plan); err == nil {
+		t.Fatal("expected the rename to fail")
+	}
+
+	saved, err := zettelRepo.FindByID(ctx, z.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Content() != "content" {
+		t.Errorf("expected the edits to be saved with the rename only, got %q", saved.Content())
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// AllocateSequence returns the first free sequence identifier in the
+// workspace for a note that continues the zettel with the given id or, when
+// branch is set, that branches off it.
+func (s *Service) AllocateSequence(ctx context.Context, workspaceID, id uuid.UUID, branch bool) (zettel.Sequence, error) {
+	z, err := s.zettelRepo.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if z.Sequence() == "" {
+		return "", zettel.ErrMissingSequence
+	}
+
+	seq := z.Sequence().Next()
+	if branch {
+		seq = z.Sequence().Branch()
+	}
+	return s.firstFreeSequence(ctx, workspaceID, seq)
+}
+
+// AllocateRootSequence returns the first free top-level sequence identifier
+// in the workspace.
+func (s *Service) AllocateRootSequence(ctx context.Context, workspaceID uuid.UUID) (zettel.Sequence, error) {
+	return s.firstFreeSequence(ctx, workspaceID, zettel.RootSequence(1))
+}
+
+func (s *Service) firstFreeSequence(ctx context.Context, workspaceID uuid.UUID, seq zettel.Sequence) (zettel.Sequence, error) {
+	for {
+		_, err := s.zettelRepo.FindBySequenceInWorkspace(ctx, workspaceID, seq)
+		if err == zettel.ErrZettelNotFound {
+			return seq, nil
+		} else if err != nil {
+			return "", err
+		}
+		seq = seq.Next()
+	}
+}
+
+// CheckSequence ensures no other zettel in the workspaces of z, or in the
+// given ones, has the sequence identifier of z.
+func (s *Service) CheckSequence(ctx context.Context, z zettel.Zettel, workspaceIDs ...uuid.UUID) error {
+	if z.Sequence() == "" {
+		return nil
+	}
+
+	workspaces, err := s.workspaceRepo.FindWorkspacesByZettelID(ctx, z.ID())
+	if err != nil {
+		return err
+	}
+	for _, wrk := range workspaces {
+		workspaceIDs = append(workspaceIDs, wrk.ID())
+	}
+
+	for _, workspaceID := range workspaceIDs {
+		other, err := s.zettelRepo.FindBySequenceInWorkspace(ctx, workspaceID, z.Sequence())
+		if err == zettel.ErrZettelNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if other.ID() != z.ID() {
+			return zettel.ErrSequenceAlreadyExists
+		}
+	}
+	return nil
+}
+
+// NextInSequence returns the zettel that continues the given one in the
+// workspace.
+func (s *Service) NextInSequence(ctx context.Context, workspaceID, id uuid.UUID) (zettel.Zettel, error) {
+	return s.sibling(ctx, workspaceID, id, 1)
+}
+
+// PrevInSequence returns the zettel the given one continues in the
+// workspace.
+func (s *Service) PrevInSequence(ctx context.Context, workspaceID, id uuid.UUID) (zettel.Zettel, error) {
+	return s.sibling(ctx, workspaceID, id, -1)
+}
+
+// ChildrenInSequence returns the zettels of the workspace that branch off the
+// given one.
+func (s *Service) ChildrenInSequence(ctx context.Context, workspaceID, id uuid.UUID) ([]zettel.Zettel, error) {
+	z, zettels, err := s.sequenceNeighbourhood(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	return zettel.Children(zettels, z.Sequence()), nil
+}
+
+// SequenceTree returns the zettels of the workspace arranged by their
+// sequence identifiers.
+func (s *Service) SequenceTree(ctx context.Context, workspaceID uuid.UUID) ([]*zettel.SequenceNode, error) {
+	zettels, err := s.zettelRepo.FindZettelsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return zettel.SequenceTree(zettels), nil
+}
+
+func (s *Service) sibling(ctx context.Context, workspaceID, id uuid.UUID, offset int) (zettel.Zettel, error) {
+	z, zettels, err := s.sequenceNeighbourhood(ctx, workspaceID, id)
+	if err != nil {
+		return zettel.Zettel{}, err
+	}
+
+	siblings := zettel.Siblings(zettels, z.Sequence())
+	for i, sibling := range siblings {
+		if sibling.ID() != z.ID() {
+			continue
+		}
+		if i+offset < 0 || i+offset >= len(siblings) {
+			break
+		}
+		return siblings[i+offset], nil
+	}
+	return zettel.Zettel{}, zettel.ErrZettelNotFound
+}
+
+// sequenceNeighbourhood returns the zettel and the zettels of the workspace.
+// The sequence identifiers are unique in a workspace only, so the sequences
+// of other workspaces are left out.
+func (s *Service) sequenceNeighbourhood(ctx context.Context, workspaceID, id uuid.UUID) (zettel.Zettel, []zettel.Zettel, error) {
+	z, err := s.zettelRepo.FindByID(ctx, id)
+	if err != nil {
+		return zettel.Zettel{}, nil, err
+	}
+	if z.Sequence() == "" {
+		return zettel.Zettel{}, nil, zettel.ErrMissingSequence
+	}
+
+	zettels, err := s.zettelRepo.FindZettelsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return zettel.Zettel{}, nil, err
+	}
+	for _, other := range zettels {
+		if other.ID() == id {
+			return z, zettels, nil
+		}
+	}
+	return zettel.Zettel{}, nil, zettel.ErrZettelNotFound
+}
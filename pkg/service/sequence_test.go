@@ -0,0 +1,139 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestService_UniqueSequences(t *testing.T) {
+	wrk := createWorkspace(t)
+
+	numbered, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	numbered.SetSequence("1")
+	z, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	for _, created := range []zettel.Zettel{numbered, z} {
+		if err := svc.CreateZettel(ctx, created, wrk.ID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type testCase struct {
+		test   string
+		change func() error
+	}
+
+	testCases := []testCase{
+		{
+			test: "Sequence saved",
+			change: func() error {
+				z.SetSequence("1")
+				return zettelRepo.Save(ctx, z)
+			},
+		},
+		{
+			test: "Sequence updated",
+			change: func() error {
+				z.SetSequence("1")
+				return zettelRepo.Update(ctx, z)
+			},
+		},
+		{
+			test: "Zettel added to the workspace",
+			change: func() error {
+				// the sequence is free where the zettel was saved
+				other, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+				other.SetSequence("1")
+				if err := zettelRepo.Save(ctx, other); err != nil {
+					return err
+				}
+				wrk, err := workspaceRepo.FindWorkspaceByID(ctx, wrk.ID())
+				if err != nil {
+					return err
+				}
+				if err := wrk.AddZettel(other.ID()); err != nil {
+					return err
+				}
+				return workspaceRepo.Save(ctx, wrk)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			err := tc.change()
+			if !errors.Is(err, zettel.ErrSequenceAlreadyExists) {
+				t.Fatalf("expected error %v, got %v", zettel.ErrSequenceAlreadyExists, err)
+			}
+			if !errors.Is(err, shared.ErrConflict) {
+				t.Errorf("expected a conflict, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Zettels without a sequence", func(t *testing.T) {
+		z.SetSequence("")
+		if err := zettelRepo.Save(ctx, z); err != nil {
+			t.Fatal(err)
+		}
+		other, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+		if err := svc.CreateZettel(ctx, other, wrk.ID()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestService_NextInSequence(t *testing.T) {
+	wrk := createWorkspace(t)
+	other := createWorkspace(t)
+
+	// the zettel starts a sequence in both workspaces, each continued by a
+	// zettel of its own
+	z, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	z.SetSequence("1")
+	if err := svc.CreateZettel(ctx, z, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.AddZettel(z.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := workspaceRepo.Save(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	next := map[uuid.UUID]zettel.Zettel{}
+	for _, workspaceID := range []uuid.UUID{wrk.ID(), other.ID()} {
+		n, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+		n.SetSequence("2")
+		if err := svc.CreateZettel(ctx, n, workspaceID); err != nil {
+			t.Fatal(err)
+		}
+		next[workspaceID] = n
+	}
+
+	for workspaceID, expected := range next {
+		got, err := svc.NextInSequence(ctx, workspaceID, z.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID() != expected.ID() {
+			t.Errorf("expected %s next in workspace %s, got %s", expected.ID(), workspaceID, got.ID())
+		}
+		prev, err := svc.PrevInSequence(ctx, workspaceID, expected.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev.ID() != z.ID() {
+			t.Errorf("expected %s before %s, got %s", z.ID(), expected.ID(), prev.ID())
+		}
+	}
+
+	// a zettel is not followed in a workspace it is not part of
+	elsewhere := createWorkspace(t)
+	_, err := svc.NextInSequence(ctx, elsewhere.ID(), z.ID())
+	if !errors.Is(err, zettel.ErrZettelNotFound) {
+		t.Errorf("expected error %v, got %v", zettel.ErrZettelNotFound, err)
+	}
+}
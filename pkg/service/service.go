@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// Service implements the use cases that span several zettels or workspaces,
+// shared by the command line and the web server.
+type Service struct {
+	workspaceRepo workspace.Repository
+	zettelRepo    zettel.Repository
+}
+
+func New(workspaceRepo workspace.Repository, zettelRepo zettel.Repository) *Service {
+	return &Service{
+		workspaceRepo: workspaceRepo,
+		zettelRepo:    zettelRepo,
+	}
+}
+
+func (s *Service) FindZettel(ctx context.Context, id uuid.UUID) (zettel.Zettel, error) {
+	return s.zettelRepo.FindByID(ctx, id)
+}
+
+// Visit records that the zettel was opened, for the history.
+func (s *Service) Visit(ctx context.Context, id uuid.UUID) error {
+	return s.zettelRepo.AddToHistory(ctx, id)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// Split splits the sections under the given headings off the zettel into new
+// zettels that join the same workspaces, and returns them.
+func (s *Service) Split(ctx context.Context, id uuid.UUID, headings []string) ([]zettel.Zettel, error) {
+	z, err := s.zettelRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	linkNames := map[uuid.UUID][]string{}
+	for _, link := range z.Links() {
+		target, err := s.zettelRepo.FindByID(ctx, link.To)
+		if err != nil {
+			return nil, err
+		}
+		linkNames[link.To] = append([]string{target.Title()}, target.Aliases()...)
+	}
+
+	parts, err := z.Split(headings, linkNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// the parts are referenced by their titles, so two sections under the
+	// same heading may not both be split off
+	titles := map[string]bool{}
+	for _, part := range parts {
+		if titles[part.Title()] {
+			return nil, fmt.Errorf("%w: %q", zettel.ErrTitleAlreadyExists, part.Title())
+		}
+		titles[part.Title()] = true
+
+		if _, err := s.zettelRepo.FindByTitle(ctx, part.Title()); err == nil {
+			return nil, zettel.ErrTitleAlreadyExists
+		} else if err != zettel.ErrZettelNotFound {
+			return nil, err
+		}
+	}
+
+	if err := s.zettelRepo.Split(ctx, z, parts); err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
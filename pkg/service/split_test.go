@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestService_Split(t *testing.T) {
+	wrk := createWorkspace(t)
+
+	// the headings are unique, so that the zettels of other tests do not
+	// take their titles
+	heading := uuid.NewString()
+	other := uuid.NewString()
+	content := "## " + heading + "\n\nfirst\n\n## " + other + "\n\nthird\n\n## " + heading + "\n\nsecond\n"
+	z, _ := zettel.New(uuid.NewString(), content, zettel.Fleet)
+	if err := svc.CreateZettel(ctx, z, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Sections under the same heading", func(t *testing.T) {
+		_, err := svc.Split(ctx, z.ID(), []string{heading, heading})
+		if !errors.Is(err, zettel.ErrTitleAlreadyExists) {
+			t.Fatalf("expected error %v, got %v", zettel.ErrTitleAlreadyExists, err)
+		}
+		unchanged, err := zettelRepo.FindByID(ctx, z.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unchanged.Content() != content {
+			t.Errorf("expected the zettel unchanged, got %q", unchanged.Content())
+		}
+	})
+
+	t.Run("Sections under different headings", func(t *testing.T) {
+		parts, err := svc.Split(ctx, z.ID(), []string{heading, other})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(parts) != 2 || parts[0].Title() != heading || parts[1].Title() != other {
+			t.Errorf("expected parts %q and %q, got %v", heading, other, parts)
+		}
+	})
+}
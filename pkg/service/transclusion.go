@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+// Transclude returns the body of z with its embeds expanded, finding the
+// embedded zettels the same way the links of the workspace are resolved.
+// wrap marks each embedded content with its source.
+func (s *Service) Transclude(ctx context.Context, z zettel.Zettel, workspaceID uuid.UUID, wrap func(ref zettel.Reference, content string) string) string {
+	t := zettel.Transclusion{
+		Find: func(name string) (zettel.Zettel, bool) {
+			id, ok := s.Resolve(ctx, workspaceID, name)
+			if !ok {
+				return zettel.Zettel{}, false
+			}
+			source, err := s.zettelRepo.FindByID(ctx, id)
+			return source, err == nil
+		},
+		Wrap:     wrap,
+		MaxDepth: zettel.DefaultTransclusionDepth,
+	}
+	return t.Expand(z)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/shared"
+	"github.com/odas0r/zet/pkg/domain/workspace"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+var (
+	ErrAmbiguousWorkspace = shared.Invalid("error: there is more than one workspace, choose one")
+)
+
+// CreateZettel saves a new zettel and adds it to the workspace.
+func (s *Service) CreateZettel(ctx context.Context, z zettel.Zettel, workspaceID uuid.UUID) error {
+	wrk, err := s.workspaceRepo.FindWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.SaveZettel(ctx, z, workspaceID); err != nil {
+		return err
+	}
+
+	if err := wrk.AddZettel(z.ID()); err != nil {
+		return err
+	}
+	return s.workspaceRepo.Save(ctx, wrk)
+}
+
+// SaveZettel saves a zettel created or edited in the given workspace. Its
+// aliases and sequence identifier must not belong to another zettel of its
+// workspaces and its links are resolved from the references in its content.
+func (s *Service) SaveZettel(ctx context.Context, z zettel.Zettel, workspaceID uuid.UUID) error {
+	z, err := s.prepareZettel(ctx, z, workspaceID)
+	if err != nil {
+		return err
+	}
+	return s.zettelRepo.Save(ctx, z)
+}
+
+// SaveRenamedZettel saves a zettel edited in the given workspace, as
+// SaveZettel does, and gives it the new title of the planned rename. The
+// edits and the rename are saved in one transaction, so that a rename that
+// fails leaves the zettel as it was.
+func (s *Service) SaveRenamedZettel(ctx context.Context, z zettel.Zettel, workspaceID uuid.UUID, plan Rename) error {
+	// the new title is checked against the aliases too
+	if err := z.Rename(plan.NewTitle); err != nil {
+		return err
+	}
+	z, err := s.prepareZettel(ctx, z, workspaceID)
+	if err != nil {
+		return err
+	}
+	plan.Zettel = z
+	return s.Rename(ctx, plan)
+}
+
+// prepareZettel checks the zettel before it is saved and resolves its links.
+func (s *Service) prepareZettel(ctx context.Context, z zettel.Zettel, workspaceID uuid.UUID) (zettel.Zettel, error) {
+	if err := s.CheckAliases(ctx, z, workspaceID); err != nil {
+		return zettel.Zettel{}, err
+	}
+	if err := s.CheckSequence(ctx, z, workspaceID); err != nil {
+		return zettel.Zettel{}, err
+	}
+
+	z.ResolveLinks(func(name string) (uuid.UUID, bool) {
+		return s.Resolve(ctx, workspaceID, name)
+	})
+	return z, nil
+}
+
+// DefaultWorkspace returns the workspace a new zettel goes to when none is
+// given: the first workspace of the zettel with the given id or, for
+// uuid.Nil, the only workspace there is.
+func (s *Service) DefaultWorkspace(ctx context.Context, zettelID uuid.UUID) (workspace.Workspace, error) {
+	var workspaces []workspace.Workspace
+	var err error
+	if zettelID != uuid.Nil {
+		workspaces, err = s.workspaceRepo.FindWorkspacesByZettelID(ctx, zettelID)
+	} else {
+		workspaces, err = s.workspaceRepo.FindAllWorkspaces(ctx)
+	}
+	if err != nil {
+		return workspace.Workspace{}, err
+	}
+
+	if len(workspaces) == 0 {
+		return workspace.Workspace{}, workspace.ErrWorkspaceNotFound
+	} else if len(workspaces) > 1 && zettelID == uuid.Nil {
+		return workspace.Workspace{}, ErrAmbiguousWorkspace
+	}
+	return workspaces[0], nil
+}
+
+// Linked returns the zettels z links to and the ones linking to it.
+func (s *Service) Linked(ctx context.Context, z zettel.Zettel) (outgoing, backlinks []zettel.Zettel, err error) {
+	for _, link := range z.Links() {
+		to, err := s.zettelRepo.FindByID(ctx, link.To)
+		if err != nil {
+			return nil, nil, err
+		}
+		outgoing = append(outgoing, to)
+	}
+
+	backlinks, err = s.zettelRepo.FindLinking(ctx, z.ID())
+	if err != nil {
+		return nil, nil, err
+	}
+	return outgoing, backlinks, nil
+}
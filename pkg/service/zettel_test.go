@@ -0,0 +1,42 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odas0r/zet/pkg/domain/zettel"
+)
+
+func TestService_SaveZettel(t *testing.T) {
+	wrk := createWorkspace(t)
+
+	// the titles are unique, so that the zettels of other tests do not
+	// resolve the references
+	target, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	removed, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	z, _ := zettel.New(uuid.NewString(), "content", zettel.Fleet)
+	for _, created := range []zettel.Zettel{target, removed, z} {
+		if err := svc.CreateZettel(ctx, created, wrk.ID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	z.SetBody("see [[" + target.Title() + "]] and [[" + removed.Title() + "]]")
+	if err := svc.SaveZettel(ctx, z, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the link goes with the reference
+	z.SetBody("see [[" + target.Title() + "]]")
+	if err := svc.SaveZettel(ctx, z, wrk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := zettelRepo.FindByID(ctx, z.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Links()) != 1 || saved.Links()[0].To != target.ID() {
+		t.Errorf("expected a link to %s only, got %v", target.ID(), saved.Links())
+	}
+}
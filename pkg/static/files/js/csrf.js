@@ -0,0 +1,15 @@
+// The forms that change something send the CSRF token of the page, which
+// the layout holds in a meta element; htmx requests send it in the header
+// set on the body instead.
+document.addEventListener("submit", (event) => {
+  const meta = document.querySelector('meta[name="csrf-token"]');
+  const form = event.target;
+  if (!meta || form.method !== "post" || form.elements.namedItem(meta.dataset.field)) {
+    return;
+  }
+  const input = document.createElement("input");
+  input.type = "hidden";
+  input.name = meta.dataset.field;
+  input.value = meta.content;
+  form.append(input);
+});
@@ -0,0 +1,15 @@
+// htmx leaves error responses out of the page. The server sends them with
+// an HX-Retarget header pointing at the #errors element, so those are
+// swapped in; a successful response clears the last error.
+document.addEventListener("htmx:beforeSwap", (event) => {
+  const xhr = event.detail.xhr;
+  if (xhr.status >= 400 && xhr.getResponseHeader("HX-Retarget")) {
+    event.detail.shouldSwap = true;
+    event.detail.isError = false;
+  } else if (xhr.status < 400) {
+    const errors = document.getElementById("errors");
+    if (errors) {
+      errors.innerHTML = "";
+    }
+  }
+});
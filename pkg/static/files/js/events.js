@@ -0,0 +1,26 @@
+// The live parts of the pages refresh themselves on the changes of the
+// zettels. While one of them is on the page, the stream of events of its
+// data-events URL is kept open, and each event is triggered on the body as
+// "zettel", which they listen to with hx-trigger="zettel from:body".
+let source = null;
+
+function connect() {
+  const live = document.querySelector("[data-events]");
+  if (!live) {
+    if (source) {
+      source.close();
+      source = null;
+    }
+    return;
+  }
+  if (source) {
+    return;
+  }
+  source = new EventSource(live.dataset.events);
+  source.addEventListener("zettel", (event) => {
+    htmx.trigger(document.body, "zettel", JSON.parse(event.data));
+  });
+}
+
+document.addEventListener("htmx:load", connect);
+document.addEventListener("htmx:afterSettle", connect);
@@ -0,0 +1,180 @@
+// Interactive graph of the zettels of a workspace.
+//
+// The page renders a form with the root zettel and the depth of the
+// neighbourhood to show; this script fetches the matching nodes and links as
+// JSON and lays them out with a small force-directed simulation: nodes repel
+// each other, links pull their ends together and everything drifts to the
+// centre. Clicking a node opens its zettel.
+(function () {
+  const SVG = "http://www.w3.org/2000/svg";
+
+  const REPULSION = 2000;
+  const SPRING = 0.02;
+  const LINK_LENGTH = 80;
+  const GRAVITY = 0.01;
+  const FRICTION = 0.85;
+  const RADIUS = 6;
+
+  const controls = document.getElementById("graph-controls");
+  const svg = document.getElementById("graph");
+  if (!controls || !svg) {
+    return;
+  }
+
+  let frame = null;
+
+  function load() {
+    const form = new FormData(controls);
+    const depth = form.get("depth");
+    controls.querySelector("output").textContent = depth === "0" ? "all" : depth;
+
+    const params = new URLSearchParams({ root: form.get("root") || "", depth });
+    fetch(controls.dataset.graphUrl + "?" + params)
+      .then((res) => {
+        if (!res.ok) {
+          throw new Error(res.statusText);
+        }
+        return res.json();
+      })
+      .then((data) => draw(data, form.get("root")))
+      .catch((err) => {
+        svg.replaceChildren(text(20, 20, "Failed to load the graph: " + err.message));
+      });
+  }
+
+  function draw(data, root) {
+    if (frame !== null) {
+      cancelAnimationFrame(frame);
+    }
+    svg.replaceChildren();
+
+    const width = svg.clientWidth;
+    const height = svg.clientHeight;
+
+    const nodes = data.nodes.map((n, i) => {
+      // start on a circle so no two nodes share a position
+      const angle = (2 * Math.PI * i) / data.nodes.length;
+      return {
+        ...n,
+        x: width / 2 + (Math.cos(angle) * width) / 4,
+        y: height / 2 + (Math.sin(angle) * height) / 4,
+        vx: 0,
+        vy: 0,
+      };
+    });
+    const byID = new Map(nodes.map((n) => [n.id, n]));
+    const links = data.edges
+      .map((e) => ({ source: byID.get(e.source), target: byID.get(e.target) }))
+      .filter((l) => l.source && l.target);
+
+    const lines = links.map((l) => {
+      const line = document.createElementNS(SVG, "line");
+      line.setAttribute("stroke", "#999");
+      svg.appendChild(line);
+      return line;
+    });
+    const groups = nodes.map((n) => {
+      const g = document.createElementNS(SVG, "g");
+      g.style.cursor = "pointer";
+      g.addEventListener("click", () => open(n));
+
+      const circle = document.createElementNS(SVG, "circle");
+      circle.setAttribute("r", n.id === root ? RADIUS * 1.5 : RADIUS);
+      circle.setAttribute("fill", n.id === root ? "#d33" : "#36c");
+      const title = document.createElementNS(SVG, "title");
+      title.textContent = n.title;
+      circle.appendChild(title);
+
+      g.appendChild(circle);
+      g.appendChild(text(RADIUS + 4, 4, n.title));
+      svg.appendChild(g);
+      return g;
+    });
+
+    let energy = Infinity;
+    function tick() {
+      // stop once the page has moved on or the layout has settled
+      if (!svg.isConnected || energy < 0.01) {
+        frame = null;
+        return;
+      }
+      energy = step(nodes, links, width, height);
+
+      links.forEach((l, i) => {
+        lines[i].setAttribute("x1", l.source.x);
+        lines[i].setAttribute("y1", l.source.y);
+        lines[i].setAttribute("x2", l.target.x);
+        lines[i].setAttribute("y2", l.target.y);
+      });
+      nodes.forEach((n, i) => {
+        groups[i].setAttribute("transform", `translate(${n.x},${n.y})`);
+      });
+      frame = requestAnimationFrame(tick);
+    }
+    tick();
+  }
+
+  // step moves the nodes one tick of the simulation and returns the kinetic
+  // energy left in the layout.
+  function step(nodes, links, width, height) {
+    for (let i = 0; i < nodes.length; i++) {
+      for (let j = i + 1; j < nodes.length; j++) {
+        const a = nodes[i];
+        const b = nodes[j];
+        const dx = b.x - a.x || Math.random() - 0.5;
+        const dy = b.y - a.y || Math.random() - 0.5;
+        const d2 = Math.max(dx * dx + dy * dy, 1);
+        const d = Math.sqrt(d2);
+        const f = REPULSION / d2;
+        a.vx -= (f * dx) / d;
+        a.vy -= (f * dy) / d;
+        b.vx += (f * dx) / d;
+        b.vy += (f * dy) / d;
+      }
+    }
+
+    for (const l of links) {
+      const dx = l.target.x - l.source.x;
+      const dy = l.target.y - l.source.y;
+      const d = Math.max(Math.sqrt(dx * dx + dy * dy), 1);
+      const f = SPRING * (d - LINK_LENGTH);
+      l.source.vx += (f * dx) / d;
+      l.source.vy += (f * dy) / d;
+      l.target.vx -= (f * dx) / d;
+      l.target.vy -= (f * dy) / d;
+    }
+
+    let energy = 0;
+    for (const n of nodes) {
+      n.vx = (n.vx + (width / 2 - n.x) * GRAVITY) * FRICTION;
+      n.vy = (n.vy + (height / 2 - n.y) * GRAVITY) * FRICTION;
+      n.x = Math.min(Math.max(n.x + n.vx, RADIUS), width - RADIUS);
+      n.y = Math.min(Math.max(n.y + n.vy, RADIUS), height - RADIUS);
+      energy += n.vx * n.vx + n.vy * n.vy;
+    }
+    return energy;
+  }
+
+  function text(x, y, content) {
+    const t = document.createElementNS(SVG, "text");
+    t.setAttribute("x", x);
+    t.setAttribute("y", y);
+    t.setAttribute("font-size", "12");
+    t.textContent = content;
+    return t;
+  }
+
+  function open(node) {
+    const url = controls.dataset.zettelUrl + node.id;
+    if (window.htmx) {
+      htmx.ajax("GET", url, { target: "#content" }).then(() => {
+        history.pushState({}, "", url);
+      });
+      return;
+    }
+    window.location.href = url;
+  }
+
+  controls.addEventListener("input", load);
+  load();
+})();
@@ -0,0 +1,105 @@
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Prefix is the path the assets are served under.
+const Prefix = "/public/"
+
+// HTMX is the name of the vendored htmx script, version 1.9.12, so that the
+// server works offline.
+const HTMX = "vendor/htmx.min.js"
+
+//go:embed files
+var embedded embed.FS
+
+var files, _ = fs.Sub(embedded, "files")
+
+// asset is a file with the hash of its content.
+type asset struct {
+	content []byte
+	hash    string
+}
+
+var (
+	// assets holds the assets by name, e.g. js/errors.js
+	assets = map[string]asset{}
+	// hashed maps the hashed names to the names, e.g. js/errors.3f2a1b9c.js
+	hashed = map[string]string{}
+)
+
+func init() {
+	fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		a := asset{content: content, hash: hex.EncodeToString(sum[:4])}
+		assets[name] = a
+		hashed[hashedName(name, a.hash)] = name
+		return nil
+	})
+}
+
+// hashedName puts the hash before the extension of the name.
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// Has tells whether there is an asset with the name.
+func Has(name string) bool {
+	_, ok := assets[name]
+	return ok
+}
+
+// Path returns the URL of the asset with the hash of its content, which
+// changes with it, so browsers may keep it for good.
+func Path(name string) string {
+	a, ok := assets[name]
+	if !ok {
+		return Prefix + name
+	}
+	return Prefix + hashedName(name, a.hash)
+}
+
+// Handler serves the assets under Prefix. The hashed URLs are cached for a
+// year, and the plain names are checked with the server on each use. A
+// Cache-Control set before, as in development mode, is kept.
+func Handler() http.Handler {
+	return http.StripPrefix(Prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, immutable := hashed[r.URL.Path]
+		if !immutable {
+			name = r.URL.Path
+		}
+		a, ok := assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		if w.Header().Get("Cache-Control") == "" {
+			if immutable {
+				w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			} else {
+				w.Header().Set("Cache-Control", "no-cache")
+			}
+		}
+		w.Header().Set("ETag", `"`+a.hash+`"`)
+		// the content type is found from the extension of the name
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(a.content))
+	}))
+}
@@ -0,0 +1,66 @@
+package static_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odas0r/zet/pkg/static"
+)
+
+func TestHandler(t *testing.T) {
+	hashed := static.Path("js/errors.js")
+	if hashed == static.Prefix+"js/errors.js" || !strings.HasSuffix(hashed, ".js") {
+		t.Fatalf("expected the hash of the content in the name, got %q", hashed)
+	}
+
+	type testCase struct {
+		test                 string
+		path                 string
+		cacheControl         string
+		expectedStatus       int
+		expectedCacheControl string
+	}
+
+	testCases := []testCase{
+		{test: "Hashed name", path: hashed, expectedStatus: http.StatusOK, expectedCacheControl: "public, max-age=31536000, immutable"},
+		{test: "Plain name", path: static.Prefix + "js/errors.js", expectedStatus: http.StatusOK, expectedCacheControl: "no-cache"},
+		{test: "Development mode", path: hashed, cacheControl: "no-store", expectedStatus: http.StatusOK, expectedCacheControl: "no-store"},
+		{test: "Unknown asset", path: static.Prefix + "js/unknown.js", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tc.cacheControl != "" {
+				rec.Header().Set("Cache-Control", tc.cacheControl)
+			}
+			static.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			if rec.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, rec.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tc.expectedCacheControl {
+				t.Errorf("expected Cache-Control %q, got %q", tc.expectedCacheControl, got)
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/javascript") {
+				t.Errorf("expected a script, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestAssets(t *testing.T) {
+	for _, name := range []string{"css/zet.css", "js/csrf.js", "js/errors.js", "js/events.js", "js/graph.js"} {
+		if !static.Has(name) {
+			t.Errorf("expected %s to be embedded", name)
+		}
+	}
+	if !static.Has(static.HTMX) {
+		t.Errorf("expected htmx to be vendored as %s", static.HTMX)
+	}
+}
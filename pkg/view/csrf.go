@@ -0,0 +1,34 @@
+package view
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// The CSRF token of a request is sent back in the form field, which
+// js/csrf.js adds to the forms of the page, or in the header of the htmx
+// requests.
+const (
+	CSRFField  = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+type csrfKey struct{}
+
+// WithCSRFToken returns a context holding the CSRF token the layout hands
+// to the forms and htmx requests of the page.
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfKey{}, token)
+}
+
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfKey{}).(string)
+	return token
+}
+
+// csrfHeaders returns the hx-headers that send the CSRF token along with
+// every htmx request.
+func csrfHeaders(ctx context.Context) string {
+	headers, _ := json.Marshal(map[string]string{CSRFHeader: CSRFToken(ctx)})
+	return string(headers)
+}
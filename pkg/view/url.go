@@ -1,11 +1,43 @@
 package view
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log/slog"
 
 	"github.com/a-h/templ"
 )
 
-func url(format string, a ...any) templ.SafeURL {
-	return templ.URL(fmt.Sprintf(format, a...))
+// URLs builds the URLs of the routes from their names, see
+// router.Router.URL.
+type URLs interface {
+	URL(name string, params ...any) (string, error)
+}
+
+type urlsKey struct{}
+
+// errNoURLs is logged when a view is rendered without the routes in its
+// context.
+var errNoURLs = errors.New("error: no routes in the context, see WithURLs")
+
+// WithURLs returns a context holding the routes the views link to.
+func WithURLs(ctx context.Context, urls URLs) context.Context {
+	return context.WithValue(ctx, urlsKey{}, urls)
+}
+
+// url returns the URL of the named route, with the params in place of its
+// wildcards. A URL that cannot be built is logged and links nowhere, so
+// that the page, often an error page already, is still rendered.
+func url(ctx context.Context, name string, params ...any) templ.SafeURL {
+	urls, ok := ctx.Value(urlsKey{}).(URLs)
+	if !ok {
+		slog.ErrorContext(ctx, "failed to build a URL", "name", name, "error", errNoURLs)
+		return "#"
+	}
+	u, err := urls.URL(name, params...)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to build a URL", "name", name, "error", err)
+		return "#"
+	}
+	return templ.URL(u)
 }